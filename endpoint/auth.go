@@ -1,6 +1,9 @@
 package endpoint
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // Auth describes a [Security Schemes] according to the [OpenAPI Specification].
 // It can be then linked to an [Endpoint]. If a MiddlewareInjector is provided,
@@ -22,3 +25,18 @@ type Auth struct {
 // the [Endpoint] it warps. This can be helpful if details of the endpoints
 // are used in the auth process.
 type AuthMiddlewareInjector func(Endpoint, http.HandlerFunc) http.HandlerFunc
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the request carrying the principal, such as
+// the name of the user, authenticated by the middleware of an [Auth]. The
+// principal is used to tell clients apart, see [KeyByPrincipal].
+func WithPrincipal(r *http.Request, principal string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+}
+
+// Principal returns the principal stored in the request via [WithPrincipal].
+func Principal(r *http.Request) (string, bool) {
+	principal, ok := r.Context().Value(principalKey{}).(string)
+	return principal, ok && principal != ""
+}
@@ -0,0 +1,319 @@
+package endpoint
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tags used on struct fields to map them to a [Parameter]. The 'in' tag is
+// mandatory and specifies the [ParameterLocation] (query, header, path or cookie),
+// all other tags are optional. If no 'name' is provided the name of the field is used.
+// The tags 'format', 'enum' (comma separated), 'minimum', 'maximum', 'pattern', 'style'
+// and 'deprecated' map to the fields of the same name of the [Parameter].
+// Fields tagged with `in:"body"` are not treated as parameters, see [Typed].
+//
+//	type ListParams struct {
+//		Limit  int    `in:"query" name:"limit" default:"10" description:"Max number of items"`
+//		Filter string `in:"header" name:"X-Filter"`
+//		Org    string `in:"path" name:"org"`
+//	}
+const (
+	TagIn          = "in"
+	TagName        = "name"
+	TagDefault     = "default"
+	TagRequired    = "required"
+	TagDescription = "description"
+	TagFormat      = "format"
+	TagEnum        = "enum"
+	TagMinimum     = "minimum"
+	TagMaximum     = "maximum"
+	TagPattern     = "pattern"
+	TagStyle       = "style"
+	TagDeprecated  = "deprecated"
+)
+
+// Violation describes a single value of a request which does not fulfill the
+// expectations of an [Endpoint].
+type Violation struct {
+	Field    string `json:"field" yaml:"field"`
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// Violations collects all [Violation]s found in a request. It implements the
+// error interface in order to report all of them at once.
+type Violations []Violation
+
+// Error returns all violations as a single string.
+func (v Violations) Error() string {
+	out := make([]string, len(v))
+	for i, violation := range v {
+		if violation.Location != "" {
+			out[i] = fmt.Sprintf("%s '%s' %s", violation.Location, violation.Field, violation.Message)
+		} else {
+			out[i] = fmt.Sprintf("'%s' %s", violation.Field, violation.Message)
+		}
+	}
+	return strings.Join(out, "; ")
+}
+
+// ParametersOf derives a list of [Parameter]s from the tags of the struct v. The
+// result can be assigned to [Endpoint.Parameters] so that the OpenAPI document
+// describes exactly what [Endpoint.Bind] reads from the request.
+func ParametersOf(v interface{}) ([]Parameter, error) {
+	fields, err := boundFields(reflect.TypeOf(v))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Parameter, len(fields))
+	for i, f := range fields {
+		out[i] = f.param
+	}
+	return out, nil
+}
+
+// Bind fills the struct pointed to by dst with the values of the request. The
+// fields of dst are mapped to parameters using struct tags (see [TagIn]). If the
+// [Endpoint] declares a [Parameter] with the same name and location, the declared
+// parameter is used to read the value, so its Default and Required fields apply.
+// All values which are missing or cannot be converted to the type of the field
+// are reported at once as [Violations].
+func (e *Endpoint) Bind(r *http.Request, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind to %T, pointer to struct required", dst)
+	}
+	fields, err := boundFields(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	violations := Violations{}
+	for _, f := range fields {
+		param := f.param
+		if declared, ok := e.parameter(param.Name, param.Location); ok {
+			param = declared
+		}
+		vals, ok, err := param.Values(r)
+		if !ok {
+			if param.Required {
+				violations = append(violations, Violation{Field: param.Name, Location: param.Location.String(), Message: "is required"})
+			}
+			continue
+		}
+		if err != nil {
+			violations = append(violations, Violation{Field: param.Name, Location: param.Location.String(), Message: err.Error()})
+			continue
+		}
+		if err := setField(rv.Elem().FieldByIndex(f.index), vals); err != nil {
+			violations = append(violations, Violation{Field: param.Name, Location: param.Location.String(), Message: err.Error()})
+		}
+	}
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+func (e *Endpoint) parameter(name string, location ParameterLocation) (Parameter, bool) {
+	for _, p := range e.Parameters {
+		if p.Name == name && p.Location == location {
+			return p, true
+		}
+	}
+	return Parameter{}, false
+}
+
+func (e *Endpoint) setParameter(param Parameter) {
+	for i, p := range e.Parameters {
+		if p.Name == param.Name && p.Location == param.Location {
+			e.Parameters[i] = param
+			return
+		}
+	}
+	e.Parameters = append(e.Parameters, param)
+}
+
+type boundField struct {
+	index []int
+	param Parameter
+}
+
+func boundFields(t reflect.Type) ([]boundField, error) {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot derive parameters from %v, struct required", t)
+	}
+	out := []boundField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		in, ok := field.Tag.Lookup(TagIn)
+		if !ok || !field.IsExported() || in == inBody {
+			continue
+		}
+		location, ok := parseParameterLocation(in)
+		if !ok {
+			return nil, fmt.Errorf("field '%s' has unknown parameter location '%s'", field.Name, in)
+		}
+		name := field.Tag.Get(TagName)
+		if name == "" {
+			name = field.Name
+		}
+		typ, ok := parameterType(field.Type)
+		if !ok {
+			return nil, fmt.Errorf("field '%s' has unsupported type %s", field.Name, field.Type)
+		}
+		required, _ := strconv.ParseBool(field.Tag.Get(TagRequired))
+		deprecated, _ := strconv.ParseBool(field.Tag.Get(TagDeprecated))
+		p := Parameter{
+			Name:        name,
+			Location:    location,
+			Required:    required || location == ParameterLocationPath,
+			Default:     field.Tag.Get(TagDefault),
+			Description: field.Tag.Get(TagDescription),
+			Type:        typ,
+			Format:      field.Tag.Get(TagFormat),
+			Pattern:     field.Tag.Get(TagPattern),
+			Style:       ParameterStyle(field.Tag.Get(TagStyle)),
+			Deprecated:  deprecated,
+		}
+		if isSlice(field.Type) {
+			p.Type, p.Items = ParameterTypeArray, typ
+		}
+		if enum := field.Tag.Get(TagEnum); enum != "" {
+			p.Enum = strings.Split(enum, ",")
+		}
+		for tag, target := range map[string]**float64{TagMinimum: &p.Minimum, TagMaximum: &p.Maximum} {
+			if val, ok := field.Tag.Lookup(tag); ok {
+				f, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s' has invalid %s '%s'", field.Name, tag, val)
+				}
+				*target = &f
+			}
+		}
+		if p.Format == "" && isTime(field.Type) {
+			p.Format = "date-time"
+		}
+		out = append(out, boundField{index: field.Index, param: p})
+	}
+	return out, nil
+}
+
+func parseParameterLocation(in string) (ParameterLocation, bool) {
+	for l, text := range parameterLocationText {
+		if text == strings.ToLower(in) {
+			return l, true
+		}
+	}
+	return ParameterLocationQuery, false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isTime(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == timeType
+}
+
+func isSlice(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func parameterType(t reflect.Type) (string, bool) {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "string", true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", true
+	case reflect.Bool:
+		return "boolean", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", true
+	case reflect.Float32, reflect.Float64:
+		return "number", true
+	case reflect.Slice:
+		return parameterType(t.Elem())
+	case reflect.Pointer:
+		return parameterType(t.Elem())
+	}
+	return "", false
+}
+
+func setField(v reflect.Value, vals []string) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setValue(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return setValue(v, vals[0])
+}
+
+func setValue(v reflect.Value, val string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val)); err != nil {
+			return fmt.Errorf("is invalid: %s", err.Error())
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a positive integer")
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		ptr := reflect.New(v.Type().Elem())
+		if err := setValue(ptr.Elem(), val); err != nil {
+			return err
+		}
+		v.Set(ptr)
+	default:
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+	return nil
+}
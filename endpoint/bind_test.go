@@ -0,0 +1,104 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type bindTestParams struct {
+	Limit   int      `in:"query" name:"limit" default:"10"`
+	Tags    []string `in:"query" name:"tag"`
+	Verbose bool     `in:"header" name:"X-Verbose"`
+	Session string   `in:"cookie" name:"session" required:"true"`
+}
+
+func TestParametersOf(t *testing.T) {
+	params, err := ParametersOf(bindTestParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	expected := []Parameter{
+		{Name: "limit", Location: ParameterLocationQuery, Default: "10", Type: "integer"},
+		{Name: "tag", Location: ParameterLocationQuery, Type: "array", Items: "string"},
+		{Name: "X-Verbose", Location: ParameterLocationHeader, Type: "boolean"},
+		{Name: "session", Location: ParameterLocationCookie, Required: true, Type: "string"},
+	}
+	if len(params) != len(expected) {
+		t.Fatalf("number of parameters is not as expected, have %d, need %d", len(params), len(expected))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(params[i], expected[i]) {
+			t.Errorf("parameter %d is not as expected, have %v, need %v", i, params[i], expected[i])
+		}
+	}
+
+	if _, err := ParametersOf(struct {
+		Foo string `in:"nowhere"`
+	}{}); err == nil {
+		t.Errorf("expected error for unknown location")
+	}
+}
+
+func TestBind(t *testing.T) {
+	cases := map[string]struct {
+		request            *http.Request
+		endpoint           Endpoint
+		expected           bindTestParams
+		expectedViolations []string
+	}{
+		"All values present": {
+			request: requestWithCookieParameters(
+				requestWithQueryParameters(
+					requestWithHeaderParameters(nil, map[string][]string{"X-Verbose": {"true"}}),
+					map[string][]string{"limit": {"3"}, "tag": {"a", "b"}}),
+				map[string]string{"session": "abc"}),
+			expected: bindTestParams{Limit: 3, Tags: []string{"a", "b"}, Verbose: true, Session: "abc"},
+		},
+		"Defaults from tags": {
+			request:  requestWithCookieParameters(nil, map[string]string{"session": "abc"}),
+			expected: bindTestParams{Limit: 10, Session: "abc"},
+		},
+		"Defaults from declared parameter": {
+			request: requestWithCookieParameters(nil, map[string]string{"session": "abc"}),
+			endpoint: Endpoint{Parameters: []Parameter{
+				{Name: "limit", Location: ParameterLocationQuery, Default: "25"},
+			}},
+			expected: bindTestParams{Limit: 25, Session: "abc"},
+		},
+		"Missing and invalid values": {
+			request: requestWithQueryParameters(
+				requestWithHeaderParameters(nil, map[string][]string{"X-Verbose": {"maybe"}}),
+				map[string][]string{"limit": {"ten"}}),
+			expectedViolations: []string{"limit", "X-Verbose", "session"},
+		},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			have := bindTestParams{}
+			err := c.endpoint.Bind(c.request, &have)
+			if len(c.expectedViolations) > 0 {
+				var violations Violations
+				if !errors.As(err, &violations) {
+					t.Fatalf("expected violations, have %v", err)
+				}
+				fields := []string{}
+				for _, v := range violations {
+					fields = append(fields, v.Field)
+				}
+				if !equal(fields, c.expectedViolations) {
+					t.Errorf("violations not as expected, have %v, need %v", fields, c.expectedViolations)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if have.Limit != c.expected.Limit || have.Verbose != c.expected.Verbose ||
+				have.Session != c.expected.Session || !equal(have.Tags, c.expected.Tags) {
+				t.Errorf("bound values not as expected, have %v, need %v", have, c.expected)
+			}
+		})
+	}
+}
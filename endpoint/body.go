@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+type bodyKey struct{}
+
+// Body returns the request body decoded by an [Endpoint] with DecodeBody enabled.
+// The value returned is of the same type as the RequestBody of the [Endpoint].
+// nil is returned if no body was decoded.
+func Body(r *http.Request) interface{} {
+	return r.Context().Value(bodyKey{})
+}
+
+// decodeBody reads the request body, validates it against the schema reflected
+// from the RequestBody of the endpoint and decodes it into a fresh value of the
+// same type. Malformed bodies are answered with 400, bodies that do not match
+// the schema with 422.
+func (e *Endpoint) decodeBody(next http.HandlerFunc) http.HandlerFunc {
+	if !e.DecodeBody || e.RequestBody == nil {
+		return next
+	}
+	t := reflect.TypeOf(e.RequestBody)
+	schema := ReflectSchema(e.RequestBody)
+	return func(w http.ResponseWriter, r *http.Request) {
+		val, status, err := decodeAndValidate(r, t, schema)
+		if err != nil {
+			e.respondError(status, "request body is invalid", err, w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), bodyKey{}, val.Elem().Interface())
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func decodeAndValidate(r *http.Request, t reflect.Type, schema *jsonschema.Schema) (reflect.Value, int, error) {
+	raw, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return reflect.Value{}, http.StatusRequestEntityTooLarge, Violations{{Field: ".", Location: "body", Message: bodyTooLarge(tooLarge.Limit)}}
+	}
+	if err != nil {
+		return reflect.Value{}, http.StatusBadRequest, Violations{{Field: ".", Location: "body", Message: "could not be read"}}
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return reflect.Value{}, http.StatusBadRequest, Violations{{Field: ".", Location: "body", Message: "is not valid JSON"}}
+	}
+	if violations := ValidateJSON(schema, data); len(violations) > 0 {
+		return reflect.Value{}, http.StatusUnprocessableEntity, violations
+	}
+	val := reflect.New(t)
+	if err := json.Unmarshal(raw, val.Interface()); err != nil {
+		return reflect.Value{}, http.StatusUnprocessableEntity, Violations{{Field: ".", Location: "body", Message: err.Error()}}
+	}
+	return val, 0, nil
+}
@@ -0,0 +1,132 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bodyTestRequest struct {
+	Name  string   `json:"name" jsonschema:"minLength=3"`
+	Count int      `json:"count,omitempty" jsonschema:"minimum=1,maximum=10"`
+	Tags  []string `json:"tags,omitempty" jsonschema:"enum=a,enum=b"`
+}
+
+func TestDecodeBody(t *testing.T) {
+	cases := map[string]struct {
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"Valid body": {
+			body:           `{"name": "todo", "count": 3, "tags": ["a"]}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "todo",
+		},
+		"Malformed body": {
+			body:           `{"name": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "is not valid JSON",
+		},
+		"Missing field": {
+			body:           `{"count": 3}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "'name' is required",
+		},
+		"Multiple violations": {
+			body:           `{"name": "to", "count": 11, "tags": ["c"], "other": true}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "body 'name' must be at least 3 characters long",
+		},
+		"Wrong type": {
+			body:           `{"name": 3}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "'name' must be of type string",
+		},
+	}
+	ep := &Endpoint{
+		RequestBody: bodyTestRequest{},
+		DecodeBody:  true,
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(Body(r).(bodyTestRequest).Name))
+		},
+	}
+	router := routerWithEndpoint(t, "/", http.MethodPost, ep)
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			w := serve(router, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body)))
+			if w.Code != c.expectedStatus {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, c.expectedStatus)
+			}
+			if !strings.Contains(w.Body.String(), c.expectedBody) {
+				t.Errorf("body %q does not contain %q", w.Body.String(), c.expectedBody)
+			}
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	violations, err := ValidateValue(bodyTestRequest{Name: "ab", Count: 20, Tags: []string{"a", "x"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	fields := []string{}
+	for _, v := range violations {
+		fields = append(fields, v.Field)
+	}
+	expected := []string{"name", "count", "tags[1]"}
+	if !equal(fields, expected) {
+		t.Errorf("violations not as expected, have %v, need %v", fields, expected)
+	}
+}
+
+func TestValidateValueNil(t *testing.T) {
+	type note struct {
+		Text string `json:"text"`
+	}
+	type todo struct {
+		Name  string     `json:"name"`
+		Notes []note     `json:"notes"`
+		Due   *time.Time `json:"due"`
+		Note  *note      `json:"note"`
+		Tags  map[string]string
+	}
+	cases := map[string]struct {
+		typed  interface{}
+		value  interface{}
+		fields []string
+	}{
+		"Nil fields": {
+			typed:  todo{},
+			value:  todo{Name: "x"},
+			fields: []string{},
+		},
+		"Nil items": {
+			typed:  []*todo{},
+			value:  []*todo{nil, {Name: "x"}},
+			fields: []string{},
+		},
+		"Null for non-nullable field": {
+			typed:  todo{},
+			value:  map[string]interface{}{"name": nil, "notes": nil, "due": nil, "note": nil, "Tags": nil},
+			fields: []string{"name"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := asJSON(tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			fields := []string{}
+			for _, v := range ValidateJSON(ReflectSchema(tc.typed), data) {
+				fields = append(fields, v.Field)
+			}
+			if !equal(fields, tc.fields) {
+				t.Errorf("violations not as expected, have %v, need %v", fields, tc.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Conflict describes a problem of the routing table of an [Endpoints] collection
+// found by [Endpoints.Validate].
+type Conflict struct {
+	Callers []Caller
+	Message string
+}
+
+// Conflicts collects all [Conflict]s found in an [Endpoints] collection. It
+// implements the error interface in order to report all of them at once.
+type Conflicts []Conflict
+
+// Error returns all conflicts as a single string.
+func (c Conflicts) Error() string {
+	out := make([]string, len(c))
+	for i, conflict := range c {
+		out[i] = conflict.Message
+	}
+	return strings.Join(out, "; ")
+}
+
+// Validate checks the routing table of the collection as a whole. It reports
+// paths which are ambiguous because they only differ in the names of their
+// parameters, prefix routes which shadow each other and operation names used
+// by more than one endpoint of the same version. The examples of all endpoints
+// are checked using [Endpoint.ValidateExamples] as well. nil is returned if no
+// conflicts are found, [Conflicts] otherwise.
+func (c Endpoints) Validate() error {
+	out := Conflicts{}
+	templates := c.templates()
+	paths := make([]string, 0, len(templates))
+	for path := range templates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for i, a := range paths {
+		for _, b := range paths[i+1:] {
+			ta, tb := templates[a], templates[b]
+			if ta.shape() != tb.shape() {
+				continue
+			}
+			conflict := Conflict{Callers: append(c.callersOf(a), c.callersOf(b)...)}
+			if ta.isPrefix {
+				conflict.Message = fmt.Sprintf("prefix route '%s' shadows prefix route '%s'", a, b)
+			} else {
+				conflict.Message = fmt.Sprintf("paths '%s' and '%s' are ambiguous", a, b)
+			}
+			out = append(out, conflict)
+		}
+	}
+	// operation names must be unique within each version
+	type operation struct{ name, version string }
+	names := map[operation][]Caller{}
+	for _, caller := range c.callers() {
+		if name := c[caller].Name; name != "" {
+			op := operation{name: name, version: caller.Version}
+			names[op] = append(names[op], caller)
+		}
+	}
+	duplicates := []operation{}
+	for op, callers := range names {
+		if len(callers) > 1 {
+			duplicates = append(duplicates, op)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].name != duplicates[j].name {
+			return duplicates[i].name < duplicates[j].name
+		}
+		return duplicates[i].version < duplicates[j].version
+	})
+	for _, op := range duplicates {
+		callers := names[op]
+		used := make([]string, len(callers))
+		for i, caller := range callers {
+			used[i] = fmt.Sprintf("%s %s", caller.Method, caller.Path)
+		}
+		message := fmt.Sprintf("operation name '%s' is used by %s", op.name, strings.Join(used, ", "))
+		if op.version != "" {
+			message = fmt.Sprintf("%s in version '%s'", message, op.version)
+		}
+		out = append(out, Conflict{Callers: callers, Message: message})
+	}
+	for _, caller := range c.callers() {
+		if err := c[caller].ValidateExamples(); err != nil {
+			message := fmt.Sprintf("%s %s: %s", caller.Method, caller.Path, err)
+			if caller.Version != "" {
+				message = fmt.Sprintf("%s in version '%s'", message, caller.Version)
+			}
+			out = append(out, Conflict{Callers: []Caller{caller}, Message: message})
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// callers returns the callers of the collection sorted by path, method and
+// version.
+func (c Endpoints) callers() []Caller {
+	out := make([]Caller, 0, len(c))
+	for caller := range c {
+		out = append(out, caller)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
+
+func (c Endpoints) callersOf(path string) []Caller {
+	out := []Caller{}
+	for _, caller := range c.callers() {
+		if caller.Path == path {
+			out = append(out, caller)
+		}
+	}
+	return out
+}
+
+// shape returns the path of the template with the names of all parameters
+// removed. Templates with the same shape match the same requests.
+func (t pathTemplate) shape() string {
+	path := t.path
+	if t.isPrefix {
+		path = t.prefix + "*"
+	}
+	return tidyParam.ReplaceAllStringFunc(path, func(param string) string {
+		return "{" + t.patterns[strings.Trim(param, "{}")] + "}"
+	})
+}
+
+// ranks returns the specificity of each segment of the template: 0 for static
+// segments, 1 for segments with constrained parameters and 2 for segments with
+// unconstrained parameters. Catch-all segments are not included.
+func (t pathTemplate) ranks() []int {
+	path := t.path
+	if t.isPrefix {
+		path = t.prefix
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	out := make([]int, len(segments))
+	for i, segment := range segments {
+		for _, param := range tidyParam.FindAllStringSubmatch(segment, -1) {
+			rank := 2
+			if _, ok := t.patterns[param[1]]; ok || param[0] != segment {
+				rank = 1
+			}
+			if rank > out[i] {
+				out[i] = rank
+			}
+		}
+	}
+	return out
+}
+
+// moreSpecific reports whether requests matching t should be routed to t rather
+// than to other in case both match. Routes which are not prefix routes are
+// always more specific than prefix routes, segments are then compared one by
+// one and longer prefixes are more specific than shorter ones.
+func (t pathTemplate) moreSpecific(other pathTemplate) bool {
+	if t.isPrefix != other.isPrefix {
+		return !t.isPrefix
+	}
+	a, b := t.ranks(), other.ranks()
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	if len(a) != len(b) {
+		return (len(a) > len(b)) == t.isPrefix
+	}
+	return t.path < other.path
+}
+
+// registration is a route attached to a [Router] by [Endpoints.Populate].
+type registration struct {
+	tpl     pathTemplate
+	route   Route
+	handler http.Handler
+}
+
+// sortRegistrations orders the registrations most specific first, which allows
+// routers with first-match semantics to route requests to the most specific route.
+func sortRegistrations(regs []registration) {
+	sort.SliceStable(regs, func(i, j int) bool {
+		a, b := regs[i], regs[j]
+		if a.tpl.path != b.tpl.path {
+			return a.tpl.moreSpecific(b.tpl)
+		}
+		if a.route.Path != b.route.Path {
+			return a.route.Path < b.route.Path
+		}
+		return a.route.Method < b.route.Method
+	})
+}
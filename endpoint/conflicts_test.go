@@ -0,0 +1,148 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestValidate(t *testing.T) {
+	type route struct {
+		path, method, name string
+	}
+	cases := map[string]struct {
+		routes    []route
+		conflicts []string
+	}{
+		"No conflicts": {
+			routes: []route{
+				{"/todos/", http.MethodGet, "list"},
+				{"/todos/{name}/", http.MethodGet, "show"},
+				{"/todos/{name}/", http.MethodPut, "finish"},
+				{"/todos/special/", http.MethodGet, ""},
+				{"/todos/{id:int}/", http.MethodDelete, ""},
+				{"/static/*/", http.MethodGet, ""},
+			},
+		},
+		"Static and parameter siblings": {
+			routes: []route{
+				{"/todos/special/", http.MethodPost, ""},
+				{"/todos/archive/", http.MethodGet, ""},
+				{"/todos/{name}/", http.MethodGet, ""},
+				{"/todos/{id:int}/", http.MethodGet, ""},
+				{"/todos/{name}/items/", http.MethodGet, ""},
+				{"/todos/special/items/", http.MethodDelete, ""},
+			},
+		},
+		"Ambiguous paths": {
+			routes: []route{
+				{"/a/{x}/", http.MethodGet, ""},
+				{"/a/{y}/", http.MethodPut, ""},
+			},
+			conflicts: []string{"paths '/a/{x}/' and '/a/{y}/' are ambiguous"},
+		},
+		"Prefix routes shadowing each other": {
+			routes: []route{
+				{"/files/*/", http.MethodGet, ""},
+				{"/files/{path...}", http.MethodGet, ""},
+			},
+			conflicts: []string{"prefix route '/files/*/' shadows prefix route '/files/{path...}'"},
+		},
+		"Duplicate operation names": {
+			routes: []route{
+				{"/a/", http.MethodGet, "show"},
+				{"/b/", http.MethodGet, "show"},
+			},
+			conflicts: []string{"operation name 'show' is used by GET /a/, GET /b/"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			endpoints := Endpoints{}
+			for _, r := range tc.routes {
+				if err := endpoints.Add(r.path, r.method, &Endpoint{Name: r.name}); err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+			}
+			err := endpoints.Validate()
+			if len(tc.conflicts) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				// collections passing validation must be accepted by all routers
+				for routerName, newRouter := range routers() {
+					func() {
+						defer func() {
+							if r := recover(); r != nil {
+								t.Errorf("populating %s is expected to succeed, have panic: %v", routerName, r)
+							}
+						}()
+						_, router := newRouter()
+						if err := endpoints.Populate(router, Config{}); err != nil {
+							t.Errorf("unexpected error populating %s: %s", routerName, err.Error())
+						}
+					}()
+				}
+				return
+			}
+			conflicts, ok := err.(Conflicts)
+			if !ok {
+				t.Fatalf("conflicts expected, have %v", err)
+			}
+			if len(conflicts) != len(tc.conflicts) {
+				t.Fatalf("conflicts are not as expected, have %v, need %v", conflicts, tc.conflicts)
+			}
+			for i, c := range conflicts {
+				if c.Message != tc.conflicts[i] {
+					t.Errorf("conflict is not as expected, have %q, need %q", c.Message, tc.conflicts[i])
+				}
+			}
+			if err := endpoints.PopulateRouter(mux.NewRouter()); err == nil {
+				t.Errorf("populate is expected to fail on conflicts")
+			}
+		})
+	}
+}
+
+func TestRegistrationOrder(t *testing.T) {
+	cases := map[string]string{
+		"/static/app.js":        "prefix",
+		"/static/special/":      "special",
+		"/static/special/x.css": "prefix",
+		"/todos/42/":            "id",
+		"/todos/abc/":           "name",
+		"/todos/new/":           "new",
+	}
+	endpoints := Endpoints{}
+	for _, e := range []struct{ path, name string }{
+		{"/static/*/", "prefix"},
+		{"/static/special/", "special"},
+		{"/todos/{name}/", "name"},
+		{"/todos/{id:int}/", "id"},
+		{"/todos/new/", "new"},
+	} {
+		name := e.name
+		endpoints.Add(e.path, http.MethodGet, &Endpoint{Name: name, Handler: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, name)
+		}})
+	}
+	// populate several times to ensure the order does not depend on map iteration
+	for i := 0; i < 10; i++ {
+		for routerName, newRouter := range routers() {
+			handler, router := newRouter()
+			if err := endpoints.Populate(router, Config{}); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			for path, expected := range cases {
+				w := serve(handler, httptest.NewRequest(http.MethodGet, path, nil))
+				if w.Body.String() != expected {
+					t.Errorf("%s: %s is expected to be routed to %s, have %q", routerName, path, expected, w.Body.String())
+				}
+			}
+		}
+	}
+}
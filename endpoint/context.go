@@ -0,0 +1,57 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+)
+
+type matchKey struct{}
+
+// match holds the endpoint serving a request. It is stored as a pointer in the
+// request context, which allows [Track] to read what was set further down the
+// handler chain.
+type match struct {
+	endpoint *Endpoint
+	caller   Caller
+}
+
+// FromContext returns the [Endpoint] serving the request as well as the [Caller]
+// it was registered with. The path of the [Caller] is the path template such as
+// '/todos/{name}/' rather than the path of the request, which makes it suitable
+// to label logs and metrics. The context of all requests dispatched via
+// [Endpoints.Populate] provides the endpoint to the middlewares of the endpoint
+// and its handler. Middlewares wrapping the router itself must be wrapped by
+// [Track] in order to read the endpoint once the request is served. False is
+// returned if no endpoint is known.
+func FromContext(ctx context.Context) (*Endpoint, Caller, bool) {
+	m, ok := ctx.Value(matchKey{}).(*match)
+	if !ok || m.endpoint == nil {
+		return nil, Caller{}, false
+	}
+	return m.endpoint, m.caller, true
+}
+
+// Track is a middleware to be used outside of the router, for example as part of
+// an alice chain. Within handlers wrapped by Track, [FromContext] returns the
+// endpoint serving the request after the next handler returned.
+func Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), matchKey{}, &match{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withEndpoint stores the endpoint in the request context. If the request was
+// tracked, the endpoint is stored in the existing match as well.
+func withEndpoint(e *Endpoint, caller Caller, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		m, ok := r.Context().Value(matchKey{}).(*match)
+		if ok {
+			m.endpoint, m.caller = e, caller
+			next(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), matchKey{}, &match{endpoint: e, caller: caller})
+		next(w, r.WithContext(ctx))
+	}
+}
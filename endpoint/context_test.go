@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	trace := []string{}
+	label := func(when string, r *http.Request) {
+		e, caller, ok := FromContext(r.Context())
+		if !ok {
+			trace = append(trace, when+":none")
+			return
+		}
+		trace = append(trace, when+":"+e.Name+" "+caller.Method+" "+caller.Path)
+	}
+
+	cfg := Config{
+		Middlewares: []Middleware{func(e Endpoint, next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				label("middleware", r)
+				next(w, r)
+			}
+		}},
+	}
+	endpoints := Endpoints{}
+	endpoints.Add("/todos/{name}/", http.MethodGet, &Endpoint{Name: "show", Handler: func(w http.ResponseWriter, r *http.Request) {
+		label("handler", r)
+	}})
+
+	cases := map[string]struct {
+		track    bool
+		method   string
+		expected []string
+	}{
+		"Request not tracked": {
+			method:   http.MethodGet,
+			expected: []string{"middleware:show GET /todos/{name}/", "handler:show GET /todos/{name}/", "outer:none"},
+		},
+		"Request tracked": {
+			track:    true,
+			method:   http.MethodGet,
+			expected: []string{"middleware:show GET /todos/{name}/", "handler:show GET /todos/{name}/", "outer:show GET /todos/{name}/"},
+		},
+		"Method answered automatically": {
+			track:    true,
+			method:   http.MethodHead,
+			expected: []string{"middleware:show HEAD /todos/{name}/", "handler:show HEAD /todos/{name}/", "outer:show HEAD /todos/{name}/"},
+		},
+		"Method not allowed": {
+			track:    true,
+			method:   http.MethodDelete,
+			expected: []string{"middleware:none", "outer:none"},
+		},
+	}
+
+	for routerName, newRouter := range routers() {
+		handler, router := newRouter()
+		endpoints.Populate(router, cfg)
+		outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, r)
+			label("outer", r)
+		})
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				trace = []string{}
+				var h http.Handler = outer
+				if tc.track {
+					h = Track(outer)
+				}
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, "/todos/x/", nil))
+				if strings.Join(trace, "; ") != strings.Join(tc.expected, "; ") {
+					t.Errorf("trace is not as expected, have %v, need %v", trace, tc.expected)
+				}
+			})
+		}
+	}
+}
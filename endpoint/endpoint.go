@@ -1,10 +1,14 @@
 package endpoint
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -28,6 +32,11 @@ type Endpoint struct {
 	// expected to be provided to this endpoint. When generating an OpenAPI Document
 	// This value is then used to generate the schema to describe the request body.
 	RequestBody interface{}
+	// DecodeBody enables the decoding of the request body before the Handler is
+	// called. The body is validated against the JSON schema reflected from the
+	// RequestBody and decoded into a fresh value of the same type, which can then
+	// be retrieved using [Body]. Invalid bodies are answered via the ErrorResponse.
+	DecodeBody bool
 	// Responses are similar to the RequestBody field, but for resposes. The keys of
 	// the map represent the HTTP status codes associated with the response.
 	// Usually it is sufficient to describe the success responses here and then use
@@ -38,13 +47,86 @@ type Endpoint struct {
 	ErrorResponse ErrorResponse
 	// Auth references the auth method used for this endpoint.
 	Auth *Auth
+	// Middlewares wrap the Handler of this endpoint, see [Middleware] for details.
+	Middlewares []Middleware
 	// Handler is the actual [http.HandlerFunc] executed when this endpoint is called
 	Handler http.HandlerFunc
+	// ErrHandler can be used instead of Handler. Errors returned are answered
+	// according to the errors registered via [Endpoint.RegisterErrorValue].
+	ErrHandler ErrHandlerFunc
+	// ErrorFallback is called for errors returned by the ErrHandler which are not
+	// registered. If not provided, such errors are logged and answered with 500.
+	ErrorFallback func(err error, w http.ResponseWriter, r *http.Request)
 	// Tags map directly to the tags field of the Operation Object according to the
 	// OpenAPI Spec.
 	Tags []string
 	// Hidden prevents the endpoint from being represented in the OpenAPI document.
 	Hidden bool
+	// Lifecycle describes the deprecation, sunset and stability of the endpoint.
+	Lifecycle Lifecycle
+	// RateLimit limits the number of requests per client, see [RateLimit].
+	RateLimit *RateLimit
+	// MaxBodyBytes limits the size of the request body. Larger bodies are answered
+	// with '413 Content Too Large' via the ErrorResponse, unlimited if not set.
+	MaxBodyBytes int64
+	// Timeout is the time the Handler has to serve a request. The context of the
+	// request is cancelled once the Timeout has passed and the request is answered
+	// with '504 Gateway Timeout' via the ErrorResponse. The Handler keeps running
+	// until it returns, it must therefore honour the context of the request and
+	// stop its work once the context is done. The response of the Handler is
+	// buffered, streaming responses are not possible if set.
+	Timeout time.Duration
+	// MaxInFlight limits the number of requests served concurrently by all routes
+	// of the endpoint. Additional requests are answered with '503 Service
+	// Unavailable' via the ErrorResponse.
+	MaxInFlight int
+	// Preconditions enable conditional requests, see [Preconditions].
+	Preconditions *Preconditions
+	// Idempotency stores and replays responses for retried requests, see [Idempotency].
+	Idempotency *Idempotency
+	// Pagination is set via [Endpoint.Paginate].
+	Pagination *Pagination
+	// Filtering is set via [Endpoint.Filterable].
+	Filtering *Filtering
+	// Examples are published in the OpenAPI document, see [Examples].
+	Examples Examples
+
+	registeredErrors []*Error
+	groupMiddlewares []Middleware
+	groupTags        []Tag
+	// templates hold the parsed paths the endpoint was added at by path
+	templates map[string]pathTemplate
+	state     *endpointState
+}
+
+// endpointState holds the state of the mechanisms of an [Endpoint] which is
+// shared by all routes the endpoint is served by, such as the HEAD route
+// answered automatically and the routes of its versions.
+type endpointState struct {
+	// now returns the current time, defaults to [time.Now]
+	now func() time.Time
+
+	limiterOnce sync.Once
+	limiter     *limiter
+	slotsOnce   sync.Once
+	slots       chan struct{}
+}
+
+// sharedState returns the state shared by the routes of the endpoint. Endpoints
+// which are not part of a collection get a state of their own.
+func (e *Endpoint) sharedState() *endpointState {
+	if e.state == nil {
+		return &endpointState{}
+	}
+	return e.state
+}
+
+// time returns the current time according to the clock of the state.
+func (s *endpointState) time() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
 }
 
 // ErrorResponse in an interface that can be implemented to ensure that all HTTP
@@ -55,42 +137,99 @@ type ErrorResponse interface {
 	Respond(status int, details string, w http.ResponseWriter, r *http.Request)
 }
 
+// ViolationResponse can be implemented in addition to [ErrorResponse] in order to
+// receive the field level [Violations] which caused an error, for example if the
+// request body does not match the schema of the [Endpoint]. ErrorResponses which
+// do not implement ViolationResponse receive the violations as part of the details.
+type ViolationResponse interface {
+	RespondViolations(status int, details string, violations Violations, w http.ResponseWriter, r *http.Request)
+}
+
 // RegisterError takes a status code as well as some details on this error and
 // registers a new [Response] to the [Endpoint]. As a result, a [http.HandlerFunc]
 // is returned which can then be used to in the [Endpoint]s handler itself.
 //
 // Using these [http.HandlerFunc]s for Error handling in the handler function of the
 // [Endpoint] is by no means neccessory but helps to ensure that all possible exit
-// code are documented in the Responses map.
+// code are documented in the Responses map. If the handler is an [ErrHandlerFunc],
+// use [Endpoint.RegisterErrorValue] instead.
 func (e *Endpoint) RegisterError(status int, details string) http.HandlerFunc {
+	return e.RegisterErrorValue(status, details).ServeHTTP
+}
+
+// RegisterErrorValue does the same as RegisterError but returns the registered
+// [Error] itself. The [Error] can be returned by the ErrHandler of the [Endpoint]
+// (either as is or wrapped) and is then answered with the registered status and
+// details via the ErrorResponse.
+func (e *Endpoint) RegisterErrorValue(status int, details string) *Error {
+	if e.Responses == nil {
+		e.Responses = map[int]interface{}{}
+	}
 	if e.ErrorResponse != nil {
 		e.Responses[status] = e.ErrorResponse
-		return func(w http.ResponseWriter, r *http.Request) {
-			e.ErrorResponse.Respond(status, details, w, r)
-		}
 	} else {
 		e.Responses[status] = details
-		return func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(status)
-			w.Write([]byte(details))
-		}
 	}
+	err := &Error{Status: status, Details: details, endpoint: e}
+	e.registeredErrors = append(e.registeredErrors, err)
+	return err
+}
+
+// respondError writes an error using the ErrorResponse of the endpoint. If err
+// holds [Violations] these are passed on to the ErrorResponse as well.
+func (e *Endpoint) respondError(status int, details string, err error, w http.ResponseWriter, r *http.Request) {
+	var violations Violations
+	errors.As(err, &violations)
+	if vr, ok := e.ErrorResponse.(ViolationResponse); ok && len(violations) > 0 {
+		vr.RespondViolations(status, details, violations, w, r)
+		return
+	}
+	if len(violations) > 0 {
+		details = fmt.Sprintf("%s: %s", details, violations.Error())
+	}
+	if e.ErrorResponse != nil {
+		e.ErrorResponse.Respond(status, details, w, r)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(details))
+}
+
+// handlerFunc returns the Handler of the endpoint wrapped in the mechanisms
+// enabled by the meta data of the endpoint.
+func (e *Endpoint) handlerFunc() http.HandlerFunc {
+	handler := e.Handler
+	if e.ErrHandler != nil {
+		handler = e.ErrHandler.handlerFunc(e)
+	}
+	// the mechanisms are applied innermost first, idempotency runs within the
+	// timeout to hold the key until the handler returns even if it timed out
+	for _, mechanism := range []func(http.HandlerFunc) http.HandlerFunc{
+		e.decodeBody, e.idempotency, e.limitBody, e.timeout, e.preconditions, e.limitInFlight, e.rateLimit, e.lifecycle,
+	} {
+		handler = mechanism(handler)
+	}
+	return handler
 }
 
 // GetParamAsString fetches a the specified parameter from wherever it is stored in the
 // given request as a string. If the value is found, it is returned
 // as the first return value and `true` as the second return value. If the value cannot
-// be found `false` will be returned as second return value.
+// be found or does not match the schema of the parameter `false` will be returned as
+// second return value.
 func (e *Endpoint) GetParamAsString(name string, r *http.Request) (string, bool) {
-	param := Parameter{}
-	for _, p := range e.Parameters {
-		if p.Name != name {
-			continue
-		}
-		param = p
-		break
+	vals, ok := e.getParam(name, r)
+	if !ok || len(vals) == 0 {
+		return "", ok
 	}
-	return param.First(r)
+	return vals[0], true
+}
+
+// GetParamAsStrings does the same as GetParamAsString but returns all values found.
+// Values of parameters of type 'array' are split according to the style of the
+// parameter.
+func (e *Endpoint) GetParamAsStrings(name string, r *http.Request) ([]string, bool) {
+	return e.getParam(name, r)
 }
 
 // GetParamAsInt fetches a the specified parameter from wherever it is stored in the
@@ -109,58 +248,217 @@ func (e *Endpoint) GetParamAsInt(name string, r *http.Request) (int, bool) {
 	return out, true
 }
 
+// GetParamAsFloat does the same as GetParamAsInt but converts the value to a float64.
+func (e *Endpoint) GetParamAsFloat(name string, r *http.Request) (float64, bool) {
+	val, ok := e.GetParamAsString(name, r)
+	if !ok {
+		return 0, false
+	}
+	out, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, false
+	}
+	return out, true
+}
+
+// GetParamAsBool does the same as GetParamAsInt but converts the value to a bool.
+func (e *Endpoint) GetParamAsBool(name string, r *http.Request) (bool, bool) {
+	val, ok := e.GetParamAsString(name, r)
+	if !ok {
+		return false, false
+	}
+	out, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, false
+	}
+	return out, true
+}
+
+// GetParamAsTime does the same as GetParamAsInt but converts the value to a [time.Time].
+// The value is expected to be formatted according to RFC 3339 or, if the format of
+// the parameter is 'date', as full-date (e.g. 2006-01-02).
+func (e *Endpoint) GetParamAsTime(name string, r *http.Request) (time.Time, bool) {
+	val, ok := e.GetParamAsString(name, r)
+	if !ok {
+		return time.Time{}, false
+	}
+	layout := time.RFC3339
+	if p, _ := e.parameterByName(name); p.Format == "date" {
+		layout = "2006-01-02"
+	}
+	out, err := time.Parse(layout, val)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return out, true
+}
+
+func (e *Endpoint) parameterByName(name string) (Parameter, bool) {
+	for _, p := range e.Parameters {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Parameter{}, false
+}
+
+func (e *Endpoint) getParam(name string, r *http.Request) ([]string, bool) {
+	param, _ := e.parameterByName(name)
+	vals, ok, err := param.Values(r)
+	if !ok || err != nil {
+		return []string{}, false
+	}
+	return vals, true
+}
+
 // Endpoints is a collection of references to an [Endpoint]. [Caller] is used to
 // uniquely identify an [Endpoint]
 type Endpoints map[Caller]*Endpoint
 
 // A Caller specifies how a [http.Request] is mapped to an [Endpoint] by
-// using the request path and HTTP method.
+// using the request path and HTTP method. The Version is set for endpoints
+// added via [Endpoints.AddVersioned].
 type Caller struct {
 	Path, Method string
+	Version      string
+}
+
+// Config holds the settings applied to all endpoints of a collection when it
+// is attached to a router via [Endpoints.Populate]. The zero value is ready to
+// use.
+type Config struct {
+	// Middlewares are applied to all endpoints of the collection, see
+	// [Middleware] for details on the order.
+	Middlewares []Middleware
+	// DisableAutoMethods turns off answering HEAD and OPTIONS automatically,
+	// see [Endpoints.Automatic]. Requests using a method not allowed are
+	// answered with '405 Method Not Allowed' regardless.
+	DisableAutoMethods bool
+	// TrailingSlashRedirect is the status code used to redirect requests using
+	// the non-canonical form of a path regarding its trailing slash to its
+	// canonical form, see [TrailingSlash]. Use 301 or 308, such requests are
+	// answered with '404 Not Found' if not set.
+	TrailingSlashRedirect int
+	// Versioning defines how requests are dispatched to the versions of the
+	// endpoints added via [Endpoints.AddVersioned].
+	Versioning Versioning
 }
 
 // Add appends an [Endpoint] to [Endpoints] at a given path and HTTP method.
+// The path can contain any number of path parameters written as
+// '{name[:constraint][ | description]}'. The constraint is either one of 'int',
+// 'number', 'bool' and 'uuid' or a regular expression the value must match.
+// A parameter written as '{name...}' captures the remainder of the path. An
+// error is returned if the path is malformed. The path is canonicalized to end
+// with a slash, use a [Group] created with [WithTrailingSlash] to apply another
+// policy.
 func (c *Endpoints) Add(path, method string, e *Endpoint) error {
+	return c.add(path, method, "", e, TrailingSlashAppend)
+}
+
+// add adds the endpoint for the version provided, unversioned if empty. The
+// [VersionPlaceholder] in the path of a versioned endpoint is replaced by the
+// version.
+func (c *Endpoints) add(path, method, version string, e *Endpoint, policy TrailingSlash) error {
 	err := checkHTTPVerbs(method)
 	if err != nil {
 		return err
 	}
-	path = preparePath(path)
-	path, params := extractPathParams(path)
-	caller := Caller{Path: path, Method: method}
+	raw := preparePath(path, policy)
+	byPath := version != "" && strings.Contains(raw, VersionPlaceholder)
+	if byPath {
+		path = strings.Replace(raw, VersionPlaceholder, version, 1)
+	}
+	tpl, err := parsePath(preparePath(path, policy))
+	if err != nil {
+		return fmt.Errorf("cannot add endpoint '%s': %w", e.Name, err)
+	}
+	// the raw path keeps the placeholder, which allows to add the endpoint
+	// again, see [Endpoints.Mount]
+	tpl.raw = raw
+	tpl.trailingSlash = policy
+	tpl.versionByPath = byPath
+	if e.state == nil {
+		e.state = &endpointState{}
+	}
+	caller := Caller{Path: tpl.path, Method: method, Version: version}
 	if _, exists := (*c)[caller]; exists {
+		if version != "" {
+			return fmt.Errorf("cannot add endpoint '%s', path '%s' with method '%s' already exists in version '%s'",
+				e.Name, tpl.path, method, version)
+		}
 		return fmt.Errorf("cannot add endpoint '%s', path '%s' with method '%s' already exists",
-			e.Name, path, method)
+			e.Name, tpl.path, method)
+	}
+	if existing, ok := c.templates()[tpl.path]; ok && !reflect.DeepEqual(existing.patterns, tpl.patterns) {
+		return fmt.Errorf("cannot add endpoint '%s', path '%s' is already used with different constraints",
+			e.Name, tpl.raw)
+	}
+	for _, p := range tpl.params {
+		if declared, ok := e.parameter(p.Name, p.Location); ok {
+			if declared.Description == "" {
+				declared.Description = p.Description
+			}
+			if declared.Pattern == "" && declared.Format == "" {
+				declared.Pattern, declared.Format = p.Pattern, p.Format
+			}
+			e.setParameter(declared)
+			continue
+		}
+		e.Parameters = append(e.Parameters, p)
+	}
+	if e.templates == nil {
+		e.templates = map[string]pathTemplate{}
+	}
+	e.templates[tpl.path] = tpl
+	if *c == nil {
+		*c = Endpoints{}
 	}
-	e.Parameters = append(e.Parameters, params...)
 	(*c)[caller] = e
 	return nil
 }
 
-// PopulateRouter takes a reference to a [github.com/gorilla/mux.Router] and
-// attaches all endpoints to it.
-func (c *Endpoints) PopulateRouter(router *mux.Router) {
-	for caller, e := range *c {
-		handler := e.Handler
-		if e.Auth != nil && e.Auth.MiddlewareInjector != nil {
-			handler = e.Auth.MiddlewareInjector(*e, handler)
-		}
-		method, path := caller.Method, caller.Path
-		if strings.HasSuffix(path, "*/") {
-			path = strings.TrimSuffix(path, "*/")
-			router.PathPrefix(path).HandlerFunc(handler).Methods(method)
-		} else {
-			router.Path(path).HandlerFunc(handler).Methods(method)
+// template returns the parsed path of the endpoint registered for the caller.
+// Paths of endpoints not added via [Endpoints.Add] are parsed on the fly.
+func (c Endpoints) template(caller Caller) pathTemplate {
+	if e, ok := c[caller]; ok {
+		if tpl, ok := e.templates[caller.Path]; ok {
+			return tpl
 		}
 	}
+	tpl, _ := parsePath(caller.Path)
+	return tpl
+}
+
+// templates returns the parsed paths of all endpoints of the collection by path.
+func (c Endpoints) templates() map[string]pathTemplate {
+	out := map[string]pathTemplate{}
+	for caller := range c {
+		out[caller.Path] = c.template(caller)
+	}
+	return out
 }
 
-func preparePath(path string) string {
+// PopulateRouter takes a reference to a [github.com/gorilla/mux.Router] and
+// attaches all endpoints to it using the zero value of [Config]. An error is
+// returned if the endpoints conflict, see [Endpoints.Populate] for details.
+func (c *Endpoints) PopulateRouter(router *mux.Router) error {
+	return c.Populate(MuxRouter(router), Config{})
+}
+
+func preparePath(path string, policy TrailingSlash) string {
 	if !strings.HasPrefix(path, "/") {
 		path = fmt.Sprintf("/%s", path)
 	}
-	if !strings.HasSuffix(path, "/") {
-		path = fmt.Sprintf("%s/", path)
+	switch policy {
+	case TrailingSlashAppend:
+		if !strings.HasSuffix(path, "/") {
+			path = fmt.Sprintf("%s/", path)
+		}
+	case TrailingSlashStrip:
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
 	}
 	return path
 }
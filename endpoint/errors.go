@@ -0,0 +1,73 @@
+package endpoint
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// Error is a sentinel error registered to an [Endpoint] via
+// [Endpoint.RegisterErrorValue]. It can be compared using [errors.Is] and is
+// also a [http.Handler] which responds with the status and details registered.
+type Error struct {
+	Status  int
+	Details string
+
+	endpoint *Endpoint
+}
+
+// Error returns the details of the error.
+func (err *Error) Error() string {
+	return err.Details
+}
+
+// ServeHTTP writes the error using the ErrorResponse of the [Endpoint] the error
+// is registered to.
+func (err *Error) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e := err.endpoint
+	if e == nil {
+		e = &Endpoint{}
+	}
+	e.respondError(err.Status, err.Details, nil, w, r)
+}
+
+// ErrHandlerFunc is an alternative to [http.HandlerFunc] that returns an error
+// rather than writing error responses itself. Set it as ErrHandler of an
+// [Endpoint] to have the returned errors answered via the ErrorResponse.
+type ErrHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (h ErrHandlerFunc) handlerFunc(e *Endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			e.handleError(err, w, r)
+		}
+	}
+}
+
+// handleError answers an error returned by a handler of the endpoint. Errors
+// registered to the endpoint take precedence, then any other [Error] and
+// [Violations] are considered. All other errors are passed to the ErrorFallback.
+func (e *Endpoint) handleError(err error, w http.ResponseWriter, r *http.Request) {
+	for _, known := range e.registeredErrors {
+		if errors.Is(err, known) {
+			e.respondError(known.Status, known.Details, err, w, r)
+			return
+		}
+	}
+	var httpErr *Error
+	if errors.As(err, &httpErr) {
+		e.respondError(httpErr.Status, httpErr.Details, err, w, r)
+		return
+	}
+	var violations Violations
+	if errors.As(err, &violations) {
+		e.respondError(http.StatusUnprocessableEntity, "request is invalid", violations, w, r)
+		return
+	}
+	if e.ErrorFallback != nil {
+		e.ErrorFallback(err, w, r)
+		return
+	}
+	log.Printf("endpoint '%s' failed to handle %s %s: %s", e.Name, r.Method, r.URL.Path, err.Error())
+	e.respondError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil, w, r)
+}
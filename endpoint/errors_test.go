@@ -0,0 +1,98 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testErrorResponse struct{}
+
+func (testErrorResponse) Respond(status int, details string, w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "error: %s", details)
+}
+
+func TestErrHandler(t *testing.T) {
+	ep := &Endpoint{ErrorResponse: testErrorResponse{}}
+	errNotFound := ep.RegisterErrorValue(http.StatusNotFound, "not found")
+	errConflict := ep.RegisterErrorValue(http.StatusConflict, "conflict")
+	unregistered := &Error{Status: http.StatusTeapot, Details: "teapot"}
+
+	cases := map[string]struct {
+		err            error
+		fallback       func(error, http.ResponseWriter, *http.Request)
+		expectedStatus int
+		expectedBody   string
+	}{
+		"No error": {
+			err:            nil,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"Registered error": {
+			err:            errNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "error: not found",
+		},
+		"Wrapped registered error": {
+			err:            fmt.Errorf("todo 'x': %w", errConflict),
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "error: conflict",
+		},
+		"Unregistered error value": {
+			err:            unregistered,
+			expectedStatus: http.StatusTeapot,
+			expectedBody:   "error: teapot",
+		},
+		"Violations": {
+			err:            Violations{{Field: "name", Message: "is required"}},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "'name' is required",
+		},
+		"Unknown error": {
+			err:            errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "error: Internal Server Error",
+		},
+		"Unknown error with fallback": {
+			err: errors.New("boom"),
+			fallback: func(err error, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte(err.Error()))
+			},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "boom",
+		},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			ep.ErrorFallback = c.fallback
+			ep.ErrHandler = func(w http.ResponseWriter, r *http.Request) error {
+				if c.err != nil {
+					return c.err
+				}
+				w.Write([]byte("ok"))
+				return nil
+			}
+			w := serve(ep.handlerFunc(), httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != c.expectedStatus {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, c.expectedStatus)
+			}
+			if !strings.Contains(w.Body.String(), c.expectedBody) {
+				t.Errorf("body %q does not contain %q", w.Body.String(), c.expectedBody)
+			}
+		})
+	}
+
+	if _, ok := ep.Responses[http.StatusNotFound]; !ok {
+		t.Errorf("registered error is expected to be documented in the responses")
+	}
+	w := serve(ep.RegisterError(http.StatusGone, "gone"), httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusGone || w.Body.String() != "error: gone" {
+		t.Errorf("handler returned by RegisterError is not as expected, have %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,129 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Example is a named example value published in the OpenAPI document.
+type Example struct {
+	Summary     string
+	Description string
+	// Value is the example value. Values of request bodies and responses are
+	// rendered as JSON, values of parameters must be strings as they would be
+	// provided in a request.
+	Value interface{}
+}
+
+// Examples holds the examples of an [Endpoint] by their name. Examples are
+// validated against the schemas reflected from the RequestBody, the Responses
+// and the Parameters of the endpoint, see [Endpoint.ValidateExamples].
+type Examples struct {
+	// RequestBody holds examples of the request body.
+	RequestBody map[string]Example
+	// Responses holds examples of the responses by status code.
+	Responses map[int]map[string]Example
+	// Parameters holds examples of the parameters by the name of the parameter.
+	Parameters map[string]map[string]Example
+}
+
+// ValidateExamples checks all Examples of the endpoint against the schemas they
+// are published with, examples of the 200 response of paginated endpoints are
+// checked against the envelope of the page. Examples of responses or parameters
+// which are not declared on the endpoint are reported as well. nil is returned
+// if all examples are valid.
+func (e *Endpoint) ValidateExamples() error {
+	problems := []string{}
+	report := func(what, name string, err error) {
+		problems = append(problems, fmt.Sprintf("example '%s' of the %s is invalid: %s", name, what, err))
+	}
+	for _, name := range exampleNames(e.Examples.RequestBody) {
+		if e.RequestBody == nil {
+			report("request body", name, fmt.Errorf("endpoint has no request body"))
+			continue
+		}
+		if err := validateExample(ReflectSchema(e.RequestBody), e.Examples.RequestBody[name].Value); err != nil {
+			report("request body", name, err)
+		}
+	}
+	codes := make([]int, 0, len(e.Examples.Responses))
+	for code := range e.Examples.Responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		what := fmt.Sprintf("%d response", code)
+		response, ok := e.Responses[code]
+		var schema *jsonschema.Schema
+		if ok && response != nil {
+			schema = ReflectSchema(response)
+			if code == http.StatusOK && e.Pagination != nil {
+				schema = e.Pagination.envelopeSchema(schema)
+			}
+		}
+		for _, name := range exampleNames(e.Examples.Responses[code]) {
+			if schema == nil {
+				report(what, name, fmt.Errorf("endpoint has no such response"))
+				continue
+			}
+			if err := validateExample(schema, e.Examples.Responses[code][name].Value); err != nil {
+				report(what, name, err)
+			}
+		}
+	}
+	params := make([]string, 0, len(e.Examples.Parameters))
+	for param := range e.Examples.Parameters {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		what := fmt.Sprintf("parameter '%s'", param)
+		p, ok := e.parameterByName(param)
+		for _, name := range exampleNames(e.Examples.Parameters[param]) {
+			if !ok {
+				report(what, name, fmt.Errorf("endpoint has no such parameter"))
+				continue
+			}
+			val, isString := e.Examples.Parameters[param][name].Value.(string)
+			if !isString {
+				report(what, name, fmt.Errorf("value must be a string"))
+				continue
+			}
+			for _, v := range p.split([]string{val}) {
+				if err := p.Validate(v); err != nil {
+					report(what, name, err)
+					break
+				}
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+func exampleNames(examples map[string]Example) []string {
+	out := make([]string, 0, len(examples))
+	for name := range examples {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// validateExample validates the value against the schema provided.
+func validateExample(schema *jsonschema.Schema, value interface{}) error {
+	data, err := asJSON(value)
+	if err != nil {
+		return err
+	}
+	if violations := ValidateJSON(schema, data); len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestValidateExamples(t *testing.T) {
+	type todo struct {
+		Name string `json:"name"`
+		Done bool   `json:"done,omitempty"`
+	}
+	cases := map[string]struct {
+		examples Examples
+		problems []string
+	}{
+		"Valid examples": {
+			examples: Examples{
+				RequestBody: map[string]Example{"shopping": {Value: todo{Name: "shopping"}}},
+				Responses:   map[int]map[string]Example{http.StatusOK: {"done": {Value: map[string]interface{}{"name": "x", "done": true}}}},
+				Parameters:  map[string]map[string]Example{"priority": {"high": {Value: "3"}}},
+			},
+		},
+		"Invalid request body example": {
+			examples: Examples{
+				RequestBody: map[string]Example{"broken": {Value: map[string]interface{}{"name": 42}}},
+			},
+			problems: []string{"example 'broken' of the request body is invalid"},
+		},
+		"Example of an undeclared response": {
+			examples: Examples{
+				Responses: map[int]map[string]Example{http.StatusCreated: {"created": {Value: todo{}}}},
+			},
+			problems: []string{"example 'created' of the 201 response is invalid: endpoint has no such response"},
+		},
+		"Parameter example out of range": {
+			examples: Examples{
+				Parameters: map[string]map[string]Example{"priority": {"urgent": {Value: "9"}}},
+			},
+			problems: []string{"example 'urgent' of the parameter 'priority' is invalid"},
+		},
+		"Parameter example which is not a string": {
+			examples: Examples{
+				Parameters: map[string]map[string]Example{"priority": {"low": {Value: 1}}},
+			},
+			problems: []string{"example 'low' of the parameter 'priority' is invalid: value must be a string"},
+		},
+		"Example of an undeclared parameter": {
+			examples: Examples{
+				Parameters: map[string]map[string]Example{"owner": {"me": {Value: "me"}}},
+			},
+			problems: []string{"example 'me' of the parameter 'owner' is invalid: endpoint has no such parameter"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			maximum := 5.0
+			e := &Endpoint{
+				RequestBody: todo{},
+				Responses:   map[int]interface{}{http.StatusOK: todo{}},
+				Parameters: []Parameter{
+					{Name: "priority", Location: ParameterLocationQuery, Type: ParameterTypeInteger, Maximum: &maximum},
+				},
+				Examples: tc.examples,
+			}
+			err := e.ValidateExamples()
+			if len(tc.problems) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("error expected, have none")
+			}
+			for _, problem := range tc.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("error is not as expected, have %q, need %q", err.Error(), problem)
+				}
+			}
+
+			endpoints := Endpoints{}
+			if err := endpoints.Add("/todos/", http.MethodPost, e); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if err := endpoints.Validate(); err == nil {
+				t.Errorf("invalid examples are expected to be reported as conflicts")
+			}
+		})
+	}
+}
+
+func TestValidateExamplesPaginated(t *testing.T) {
+	type todo struct {
+		Name string `json:"name"`
+	}
+	cases := map[string]struct {
+		envelope Envelope
+		value    interface{}
+		problem  string
+	}{
+		"Page in the default envelope": {
+			value: map[string]interface{}{"items": []todo{{Name: "shopping"}}, "total": 1, "next": "/todos/?limit=1&offset=1"},
+		},
+		"Page in a custom envelope": {
+			envelope: Envelope{Items: "data", Total: "count"},
+			value:    map[string]interface{}{"data": []todo{{Name: "shopping"}}, "count": 1},
+		},
+		"Page with an invalid item": {
+			value:   map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": 42}}},
+			problem: "example 'page' of the 200 response is invalid",
+		},
+		"Items without envelope": {
+			value:   []todo{{Name: "shopping"}},
+			problem: "example 'page' of the 200 response is invalid",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &Endpoint{
+				Responses: map[int]interface{}{http.StatusOK: []todo{}},
+				Examples:  Examples{Responses: map[int]map[string]Example{http.StatusOK: {"page": {Value: tc.value}}}},
+			}
+			e.Paginate(Pagination{Envelope: tc.envelope})
+			err := e.ValidateExamples()
+			if tc.problem == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.problem) {
+				t.Errorf("error is not as expected, have %v, need %q", err, tc.problem)
+			}
+		})
+	}
+}
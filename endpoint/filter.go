@@ -0,0 +1,479 @@
+package endpoint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterOperator compares the value of a field with the values of a [Condition].
+type FilterOperator string
+
+const (
+	OperatorEq       FilterOperator = "eq"       // the field equals the value, e.g. 'filter[name]=shopping' or 'filter[name][eq]=shopping'
+	OperatorNe       FilterOperator = "ne"       // the field does not equal the value, e.g. 'filter[name][ne]=shopping'
+	OperatorLt       FilterOperator = "lt"       // the field is less than the value, e.g. 'filter[due][lt]=2024-06-01T00:00:00Z'
+	OperatorGt       FilterOperator = "gt"       // the field is greater than the value, e.g. 'filter[due][gt]=2024-06-01T00:00:00Z'
+	OperatorIn       FilterOperator = "in"       // the field equals one of the comma separated values, e.g. 'filter[name][in]=shopping,laundry'
+	OperatorContains FilterOperator = "contains" // the string field contains the value, e.g. 'filter[name][contains]=shop'
+)
+
+// Filtering declares which fields of the items of a list returned by an
+// [Endpoint] can be filtered and sorted by. Fields are referenced by the name
+// used in their JSON representation. Use [Endpoint.Filterable] to add filtering
+// and sorting to an endpoint.
+type Filtering struct {
+	// Filter maps the fields which can be filtered by to the operators allowed.
+	Filter map[string][]FilterOperator
+	// Sort lists the fields which can be sorted by.
+	Sort []string
+
+	item   reflect.Type
+	fields map[string]field
+}
+
+// field is a field of the item type of a list.
+type field struct {
+	index []int
+	typ   reflect.Type
+}
+
+// Condition is a single filter condition of a [ListQuery]. The Values are of
+// the type of the field, there is more than one value for [OperatorIn] only.
+type Condition struct {
+	Field    string
+	Operator FilterOperator
+	Values   []interface{}
+}
+
+// SortKey is a field a [ListQuery] is sorted by.
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ListQuery holds the filter conditions and sort keys requested by a client as
+// parsed by [Endpoint.ParseQuery]. All Conditions must match for an item to be
+// included. Each filter parameter can be provided once, [OperatorIn] matches
+// one of several values.
+type ListQuery struct {
+	Filter []Condition
+	Sort   []SortKey
+
+	fields map[string]field
+}
+
+// Filterable declares filtering and sorting on the endpoint. The item type is
+// derived from the 200 response of the endpoint, which must be a slice. A query
+// parameter 'filter[field][operator]' is added to the Parameters of the endpoint
+// for each field and operator allowed as well as the 'sort' parameter, which
+// takes a comma separated list of fields optionally prefixed by '-' to sort in
+// descending order. Lists returned by a handler created via [Typed] are filtered
+// and sorted automatically, other handlers use [Endpoint.ParseQuery] and
+// [ListQuery.Apply]. Fields of string, boolean and numeric kind as well as
+// [time.Time] and pointers to those are supported. An error is returned if a
+// field does not exist or its type is not supported.
+func (e *Endpoint) Filterable(f Filtering) error {
+	t := reflect.TypeOf(e.Responses[http.StatusOK])
+	if t == nil || t.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot filter endpoint '%s', its 200 response is not a list", e.Name)
+	}
+	f.item = t.Elem()
+	for f.item.Kind() == reflect.Pointer {
+		f.item = f.item.Elem()
+	}
+	f.fields = jsonFields(f.item)
+
+	names := make([]string, 0, len(f.Filter))
+	for name := range f.Filter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := []Parameter{}
+	for _, name := range names {
+		fld, ok := f.fields[name]
+		if !ok {
+			return fmt.Errorf("cannot filter endpoint '%s' by '%s', %s has no such field", e.Name, name, f.item)
+		}
+		if !comparableType(fld.typ) {
+			return fmt.Errorf("cannot filter endpoint '%s' by '%s', fields of type %s are not supported", e.Name, name, fld.typ)
+		}
+		for _, op := range f.Filter[name] {
+			if op == OperatorContains && fld.typ.Kind() != reflect.String {
+				return fmt.Errorf("cannot filter endpoint '%s' by '%s' using '%s', the field is not a string", e.Name, name, op)
+			}
+			params = append(params, filterParameter(name, op, fld.typ))
+		}
+	}
+	for _, name := range f.Sort {
+		fld, ok := f.fields[name]
+		if !ok {
+			return fmt.Errorf("cannot sort endpoint '%s' by '%s', %s has no such field", e.Name, name, f.item)
+		}
+		if !comparableType(fld.typ) {
+			return fmt.Errorf("cannot sort endpoint '%s' by '%s', fields of type %s are not supported", e.Name, name, fld.typ)
+		}
+	}
+	if len(f.Sort) > 0 {
+		fields := strings.Join(quoteAll(f.Sort), "|")
+		params = append(params, Parameter{
+			Name:        "sort",
+			Location:    ParameterLocationQuery,
+			Type:        ParameterTypeString,
+			Description: fmt.Sprintf("Comma separated fields to sort by, prefixed by '-' for descending order: %s", strings.Join(f.Sort, ", ")),
+			Pattern:     fmt.Sprintf("^-?(%s)(,-?(%s))*$", fields, fields),
+		})
+	}
+	e.Parameters = append(e.Parameters, params...)
+	e.Filtering = &f
+	return nil
+}
+
+// comparableType reports whether values of fields of the type can be compared
+// by [compare].
+func comparableType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func quoteAll(in []string) []string {
+	out := make([]string, len(in))
+	for i, s := range in {
+		out[i] = regexp.QuoteMeta(s)
+	}
+	return out
+}
+
+// filterParameter returns the query parameter of a field and operator.
+func filterParameter(name string, op FilterOperator, t reflect.Type) Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	typ, _ := parameterType(t)
+	p := Parameter{
+		Name:     fmt.Sprintf("filter[%s][%s]", name, op),
+		Location: ParameterLocationQuery,
+		Type:     typ,
+	}
+	if op == OperatorEq {
+		p.Name = fmt.Sprintf("filter[%s]", name)
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		p.Format = "date-time"
+	}
+	switch op {
+	case OperatorEq:
+		p.Description = fmt.Sprintf("Only include items whose '%s' equals the value", name)
+	case OperatorNe:
+		p.Description = fmt.Sprintf("Only include items whose '%s' does not equal the value", name)
+	case OperatorLt:
+		p.Description = fmt.Sprintf("Only include items whose '%s' is less than the value", name)
+	case OperatorGt:
+		p.Description = fmt.Sprintf("Only include items whose '%s' is greater than the value", name)
+	case OperatorIn:
+		p.Description = fmt.Sprintf("Only include items whose '%s' equals one of the comma separated values", name)
+		p.Type, p.Items, p.Style = ParameterTypeArray, p.Type, ParameterStyleSimple
+	case OperatorContains:
+		p.Description = fmt.Sprintf("Only include items whose '%s' contains the value", name)
+	}
+	return p
+}
+
+// jsonFields returns the exported fields of a struct type by their JSON name.
+func jsonFields(t reflect.Type) map[string]field {
+	out := map[string]field{}
+	if t.Kind() != reflect.Struct {
+		return out
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out[name] = field{index: f.Index, typ: f.Type}
+	}
+	return out
+}
+
+var filterKey = regexp.MustCompile(`^filter\[([^\]]+)\](?:\[([^\]]+)\])?$`)
+
+// ParseQuery parses the filter conditions and sort keys of the request. Fields
+// and operators not declared via [Endpoint.Filterable], filter parameters
+// provided more than once as well as values which cannot be converted to the
+// type of their field are reported as [Violations].
+func (e *Endpoint) ParseQuery(r *http.Request) (ListQuery, error) {
+	if e.Filtering == nil {
+		return ListQuery{}, fmt.Errorf("endpoint '%s' is not filterable", e.Name)
+	}
+	f := e.Filtering
+	out := ListQuery{fields: f.fields}
+	violations := Violations{}
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		m := filterKey.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+		name, op := m[1], FilterOperator(m[2])
+		if op == "" {
+			op = OperatorEq
+		}
+		allowed, ok := f.Filter[name]
+		if !ok {
+			violations = append(violations, Violation{Field: key, Location: "query", Message: fmt.Sprintf("cannot filter by '%s'", name)})
+			continue
+		}
+		if !containsOperator(allowed, op) {
+			violations = append(violations, Violation{Field: key, Location: "query", Message: fmt.Sprintf("operator '%s' is not supported", op)})
+			continue
+		}
+		raw := query[key]
+		if len(raw) > 1 {
+			violations = append(violations, Violation{Field: key, Location: "query", Message: fmt.Sprintf("must be provided once, use '%s' to match one of several values", OperatorIn)})
+			continue
+		}
+		if op == OperatorIn {
+			raw = strings.Split(raw[0], ",")
+		}
+		cond := Condition{Field: name, Operator: op}
+		for _, val := range raw {
+			v, err := parseValue(val, f.fields[name].typ)
+			if err != nil {
+				violations = append(violations, Violation{Field: key, Location: "query", Message: err.Error()})
+				break
+			}
+			cond.Values = append(cond.Values, v)
+		}
+		out.Filter = append(out.Filter, cond)
+	}
+	if s := query.Get("sort"); s != "" {
+		for _, name := range strings.Split(s, ",") {
+			key := SortKey{Field: strings.TrimPrefix(name, "-"), Descending: strings.HasPrefix(name, "-")}
+			if !contains(f.Sort, key.Field) {
+				violations = append(violations, Violation{Field: "sort", Location: "query", Message: fmt.Sprintf("cannot sort by '%s'", key.Field)})
+				continue
+			}
+			out.Sort = append(out.Sort, key)
+		}
+	}
+	if len(violations) > 0 {
+		return out, violations
+	}
+	return out, nil
+}
+
+func containsOperator(ops []FilterOperator, op FilterOperator) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseValue converts the value into the type of a field.
+func parseValue(val string, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		v, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return nil, fmt.Errorf("must be a date-time")
+		}
+		return v, nil
+	}
+	typ, _ := parameterType(t)
+	switch typ {
+	case ParameterTypeBoolean:
+		v, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return v, nil
+	case ParameterTypeInteger:
+		// integers are compared as such, as float64 cannot represent all of them
+		var v interface{}
+		var err error
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v, err = strconv.ParseUint(val, 10, t.Bits())
+		default:
+			v, err = strconv.ParseInt(val, 10, t.Bits())
+		}
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, fmt.Errorf("is out of range")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return v, nil
+	case ParameterTypeNumber:
+		v, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return v, nil
+	}
+	return val, nil
+}
+
+// Apply filters and sorts the items provided, which must be a slice of the item
+// type of the endpoint the query was parsed by. A new slice of the same type is
+// returned, the items provided are left untouched. Fields without value such as
+// nil pointers never match a condition, not even [OperatorNe]. They are not
+// ordered either, items without value are placed after all others regardless
+// of the sort order.
+func (q ListQuery) Apply(items interface{}) (interface{}, error) {
+	list := reflect.ValueOf(items)
+	if list.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot filter %T, a slice is required", items)
+	}
+	out := reflect.MakeSlice(list.Type(), 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		if q.matches(list.Index(i)) {
+			out = reflect.Append(out, list.Index(i))
+		}
+	}
+	sort.SliceStable(out.Interface(), func(i, j int) bool {
+		for _, key := range q.Sort {
+			a, b := q.value(out.Index(i), key.Field), q.value(out.Index(j), key.Field)
+			c, valid := compare(a, b)
+			if !valid && (a == nil) != (b == nil) {
+				// items without value are sorted last regardless of the order
+				return b == nil
+			}
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != key.Descending
+		}
+		return false
+	})
+	return out.Interface(), nil
+}
+
+// matches reports whether the item matches all conditions of the query.
+func (q ListQuery) matches(item reflect.Value) bool {
+	for _, cond := range q.Filter {
+		v := q.value(item, cond.Field)
+		ok := false
+		switch cond.Operator {
+		case OperatorEq, OperatorIn:
+			for _, want := range cond.Values {
+				c, valid := compare(v, want)
+				ok = ok || (valid && c == 0)
+			}
+		case OperatorNe:
+			c, valid := compare(v, cond.Values[0])
+			ok = valid && c != 0
+		case OperatorLt:
+			c, valid := compare(v, cond.Values[0])
+			ok = valid && c < 0
+		case OperatorGt:
+			c, valid := compare(v, cond.Values[0])
+			ok = valid && c > 0
+		case OperatorContains:
+			s, _ := v.(string)
+			ok = strings.Contains(s, cond.Values[0].(string))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// value returns the value of the field of the item converted to the types
+// used by the values of a [Condition].
+func (q ListQuery) value(item reflect.Value, name string) interface{} {
+	for item.Kind() == reflect.Pointer {
+		if item.IsNil() {
+			return nil
+		}
+		item = item.Elem()
+	}
+	v := item.FieldByIndex(q.fields[name].index)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	}
+	return v.Interface()
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater than b.
+// False is returned if the values cannot be compared, which is the case if
+// either is nil or their types differ. Such values are neither equal nor ordered.
+func compare(a, b interface{}) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		b, ok := b.(int64)
+		return cmp.Compare(a, b), ok
+	case uint64:
+		b, ok := b.(uint64)
+		return cmp.Compare(a, b), ok
+	case float64:
+		b, ok := b.(float64)
+		return cmp.Compare(a, b), ok
+	case string:
+		b, ok := b.(string)
+		return strings.Compare(a, b), ok
+	case bool:
+		b, ok := b.(bool)
+		if a == b {
+			return 0, ok
+		} else if !a {
+			return -1, ok
+		}
+		return 1, ok
+	case time.Time:
+		b, ok := b.(time.Time)
+		return a.Compare(b), ok
+	}
+	return 0, false
+}
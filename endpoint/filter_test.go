@@ -0,0 +1,279 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type filterItem struct {
+	Name     string    `json:"name"`
+	Done     bool      `json:"done"`
+	Priority int       `json:"priority"`
+	Due      time.Time `json:"due"`
+	Owner    *string   `json:"owner"`
+	ID       testUUID  `json:"id"`
+	Secret   string    `json:"-"`
+}
+
+type testUUID [16]byte
+
+func (u testUUID) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(u[:])), nil
+}
+
+func (u *testUUID) UnmarshalText(text []byte) error {
+	_, err := hex.Decode(u[:], text)
+	return err
+}
+
+func TestFilterable(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	alice, bob := "alice", "bob"
+	items := []*filterItem{
+		{Name: "shopping", Priority: 2, Due: day(3), Owner: &alice},
+		{Name: "laundry", Done: true, Priority: 1, Due: day(1)},
+		{Name: "dishes", Priority: 2, Due: day(2), Owner: &bob},
+		{Name: "shoes", Done: true, Priority: 3, Due: day(4)},
+	}
+	filtering := Filtering{
+		Filter: map[string][]FilterOperator{
+			"name":     {OperatorEq, OperatorIn, OperatorContains},
+			"done":     {OperatorEq, OperatorNe},
+			"priority": {OperatorLt, OperatorGt},
+			"due":      {OperatorLt},
+			"owner":    {OperatorEq, OperatorNe, OperatorLt},
+		},
+		Sort: []string{"name", "priority", "due", "owner"},
+	}
+
+	cases := map[string]struct {
+		query  string
+		status int
+		names  []string
+	}{
+		"Without filter": {
+			status: http.StatusOK,
+			names:  []string{"shopping", "laundry", "dishes", "shoes"},
+		},
+		"Equal": {
+			query:  "filter[name]=dishes",
+			status: http.StatusOK,
+			names:  []string{"dishes"},
+		},
+		"One of several values": {
+			query:  "filter[name][in]=dishes,shoes",
+			status: http.StatusOK,
+			names:  []string{"dishes", "shoes"},
+		},
+		"Containing a value": {
+			query:  "filter[name][contains]=sho",
+			status: http.StatusOK,
+			names:  []string{"shopping", "shoes"},
+		},
+		"Not equal": {
+			query:  "filter[done][ne]=true",
+			status: http.StatusOK,
+			names:  []string{"shopping", "dishes"},
+		},
+		"Combined conditions": {
+			query:  "filter[priority][gt]=1&filter[due][lt]=2024-06-04T00:00:00Z",
+			status: http.StatusOK,
+			names:  []string{"shopping", "dishes"},
+		},
+		"Sorted by several fields": {
+			query:  "sort=-priority,name",
+			status: http.StatusOK,
+			names:  []string{"shoes", "dishes", "shopping", "laundry"},
+		},
+		"Sorted by time": {
+			query:  "sort=due",
+			status: http.StatusOK,
+			names:  []string{"laundry", "dishes", "shopping", "shoes"},
+		},
+		"Equal with nil values": {
+			query:  "filter[owner]=alice",
+			status: http.StatusOK,
+			names:  []string{"shopping"},
+		},
+		"Not equal with nil values": {
+			query:  "filter[owner][ne]=alice",
+			status: http.StatusOK,
+			names:  []string{"dishes"},
+		},
+		"Less than with nil values": {
+			query:  "filter[owner][lt]=carol",
+			status: http.StatusOK,
+			names:  []string{"shopping", "dishes"},
+		},
+		"Sorted with nil values last": {
+			query:  "sort=owner",
+			status: http.StatusOK,
+			names:  []string{"shopping", "dishes", "laundry", "shoes"},
+		},
+		"Sorted descending with nil values last": {
+			query:  "sort=-owner",
+			status: http.StatusOK,
+			names:  []string{"dishes", "shopping", "laundry", "shoes"},
+		},
+		"Repeated filter parameter": {
+			query:  "filter[name]=dishes&filter[name]=shoes",
+			status: http.StatusBadRequest,
+		},
+		"Equal and one of several values": {
+			query:  "filter[name]=dishes&filter[name][in]=dishes,shoes",
+			status: http.StatusOK,
+			names:  []string{"dishes"},
+		},
+		"Unknown field": {
+			query:  "filter[secret]=x",
+			status: http.StatusBadRequest,
+		},
+		"Unsupported operator": {
+			query:  "filter[priority][eq]=2",
+			status: http.StatusBadRequest,
+		},
+		"Invalid value": {
+			query:  "filter[priority][lt]=high",
+			status: http.StatusBadRequest,
+		},
+		"Unknown sort field": {
+			query:  "sort=done",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := Typed(func(ctx context.Context, in struct{}) ([]*filterItem, error) {
+				return items, nil
+			})
+			if err := e.Filterable(filtering); err != nil {
+				t.Fatalf("declaring filters failed: %s", err)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/todos/", nil)
+			r.URL.RawQuery = tc.query
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, r)
+			if w.Code != tc.status {
+				t.Fatalf("status is not as expected, have %d, need %d: %s", w.Code, tc.status, w.Body.String())
+			}
+			if tc.names == nil {
+				return
+			}
+			out := []filterItem{}
+			if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+				t.Fatalf("response could not be decoded: %s", err)
+			}
+			names := []string{}
+			for _, item := range out {
+				names = append(names, item.Name)
+			}
+			if !reflect.DeepEqual(names, tc.names) {
+				t.Errorf("items are not as expected, have %v, need %v", names, tc.names)
+			}
+		})
+	}
+
+	if items[0].Name != "shopping" {
+		t.Errorf("items provided are expected to be left untouched")
+	}
+}
+
+func TestFilterableParameters(t *testing.T) {
+	e := Typed(func(ctx context.Context, in struct{}) ([]filterItem, error) { return nil, nil })
+	err := e.Filterable(Filtering{
+		Filter: map[string][]FilterOperator{"priority": {OperatorEq, OperatorIn}},
+		Sort:   []string{"name"},
+	})
+	if err != nil {
+		t.Fatalf("declaring filters failed: %s", err)
+	}
+	names := []string{}
+	for _, p := range e.Parameters {
+		names = append(names, p.Name)
+	}
+	if expected := []string{"filter[priority]", "filter[priority][in]", "sort"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("parameters are not as expected, have %v, need %v", names, expected)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/?"+url.Values{"sort": {"name"}}.Encode(), nil)
+	if _, ok, err := e.Parameters[2].Values(r); !ok || err != nil {
+		t.Errorf("sort parameter is expected to be valid, have %v", err)
+	}
+
+	for name, f := range map[string]Filtering{
+		"Unknown field":            {Filter: map[string][]FilterOperator{"secret": {OperatorEq}}},
+		"Unknown sort field":       {Sort: []string{"assignee"}},
+		"Contains on a non-string": {Filter: map[string][]FilterOperator{"done": {OperatorContains}}},
+		"Unsupported field type":   {Filter: map[string][]FilterOperator{"id": {OperatorEq}}},
+		"Unsupported sort type":    {Sort: []string{"id"}},
+	} {
+		e := Typed(func(ctx context.Context, in struct{}) ([]filterItem, error) { return nil, nil })
+		if err := e.Filterable(f); err == nil {
+			t.Errorf("%s: declaring filters is expected to fail", name)
+		}
+	}
+}
+
+func TestFilterLargeIntegers(t *testing.T) {
+	type item struct {
+		Signed   int64  `json:"signed"`
+		Unsigned uint64 `json:"unsigned"`
+		Small    int8   `json:"small"`
+	}
+	// both values are represented by the same float64
+	items := []item{
+		{Signed: 1<<53 + 1, Unsigned: 1<<63 + 1},
+		{Signed: 1 << 53, Unsigned: 1 << 63},
+	}
+	cases := map[string]struct {
+		query  string
+		status int
+		count  int
+	}{
+		"Signed integer above 2^53":        {query: "filter[signed]=9007199254740993", status: http.StatusOK, count: 1},
+		"Signed integer greater than 2^53": {query: "filter[signed][gt]=9007199254740992", status: http.StatusOK, count: 1},
+		"Unsigned integer above 2^63":      {query: "filter[unsigned]=9223372036854775809", status: http.StatusOK, count: 1},
+		"Unsigned integer out of range":    {query: "filter[unsigned]=18446744073709551616", status: http.StatusBadRequest},
+		"Small integer out of range":       {query: "filter[small]=128", status: http.StatusBadRequest},
+		"Negative unsigned integer":        {query: "filter[unsigned]=-1", status: http.StatusBadRequest},
+		"Fraction for an integer field":    {query: "filter[signed]=1.5", status: http.StatusBadRequest},
+		"Exponent notation for an integer": {query: "filter[signed]=1e3", status: http.StatusBadRequest},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := Typed(func(ctx context.Context, in struct{}) ([]item, error) { return items, nil })
+			err := e.Filterable(Filtering{Filter: map[string][]FilterOperator{
+				"signed":   {OperatorEq, OperatorGt},
+				"unsigned": {OperatorEq},
+				"small":    {OperatorEq},
+			}})
+			if err != nil {
+				t.Fatalf("declaring filters failed: %s", err)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.URL.RawQuery = tc.query
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, r)
+			if w.Code != tc.status {
+				t.Fatalf("status is not as expected, have %d, need %d: %s", w.Code, tc.status, w.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			out := []item{}
+			if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+				t.Fatalf("response could not be decoded: %s", err)
+			}
+			if len(out) != tc.count {
+				t.Errorf("number of items is not as expected, have %v, need %d", out, tc.count)
+			}
+		})
+	}
+}
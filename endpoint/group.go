@@ -0,0 +1,224 @@
+package endpoint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Group is a subset of [Endpoints] sharing a common path prefix as well as some
+// defaults. Use [Endpoints.Group] to create a Group, all endpoints added to the
+// Group are added to the [Endpoints] it was created from.
+type Group struct {
+	prefix        string
+	tags          []Tag
+	auth          *Auth
+	errorResponse ErrorResponse
+	middlewares   []Middleware
+	trailingSlash *TrailingSlash
+	parent        *Endpoints
+}
+
+// Tag is used to categorize endpoints. Tags of groups are rendered along with
+// their descriptions in the OpenAPI document.
+type Tag struct {
+	Name        string
+	Description string
+}
+
+// GroupOption configures the defaults of a [Group].
+type GroupOption func(*Group)
+
+// WithTag adds a tag to all endpoints of the group. The description is used to
+// describe the tag in the OpenAPI document.
+func WithTag(name, description string) GroupOption {
+	return func(g *Group) {
+		g.tags = append(g.tags, Tag{Name: name, Description: description})
+	}
+}
+
+// WithAuth sets the [Auth] of all endpoints of the group which do not provide
+// their own.
+func WithAuth(auth *Auth) GroupOption {
+	return func(g *Group) {
+		g.auth = auth
+	}
+}
+
+// WithErrorResponse sets the [ErrorResponse] of all endpoints of the group which
+// do not provide their own.
+func WithErrorResponse(er ErrorResponse) GroupOption {
+	return func(g *Group) {
+		g.errorResponse = er
+	}
+}
+
+// WithMiddlewares adds [Middleware]s which are applied to all endpoints of the group.
+func WithMiddlewares(middlewares ...Middleware) GroupOption {
+	return func(g *Group) {
+		g.middlewares = append(g.middlewares, middlewares...)
+	}
+}
+
+// Group creates a new [Group] which adds its endpoints to c below the prefix provided.
+func (c *Endpoints) Group(prefix string, opts ...GroupOption) *Group {
+	g := &Group{prefix: prefix, parent: c}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Group creates a nested [Group]. The nested group inherits the prefix and all
+// defaults of g, tags and middlewares provided are appended to those of g.
+func (g *Group) Group(prefix string, opts ...GroupOption) *Group {
+	nested := &Group{
+		prefix:        joinPath(g.prefix, prefix),
+		tags:          append([]Tag{}, g.tags...),
+		auth:          g.auth,
+		errorResponse: g.errorResponse,
+		middlewares:   append([]Middleware{}, g.middlewares...),
+		trailingSlash: g.trailingSlash,
+		parent:        g.parent,
+	}
+	for _, opt := range opts {
+		opt(nested)
+	}
+	return nested
+}
+
+// Add appends an [Endpoint] to the [Endpoints] of the group at the prefix of the
+// group joined with the path provided. The defaults of the group are applied to
+// the [Endpoint]. The path is canonicalized using the [TrailingSlash] policy of
+// the group.
+func (g *Group) Add(path, method string, e *Endpoint) error {
+	return g.add(path, method, "", e, TrailingSlashAppend)
+}
+
+// AddVersioned appends an [Endpoint] for each of the versions provided to the
+// [Endpoints] of the group, see [Endpoints.AddVersioned]. The prefix of the
+// group may contain the [VersionPlaceholder] as well.
+func (g *Group) AddVersioned(path, method string, e *Endpoint, versions ...string) error {
+	g.apply(e)
+	return g.parent.addVersioned(joinPath(g.prefix, path), method, e, g.policy(TrailingSlashAppend), versions...)
+}
+
+// add adds the endpoint using the policy provided unless the group has a policy
+// of its own.
+func (g *Group) add(path, method, version string, e *Endpoint, policy TrailingSlash) error {
+	g.apply(e)
+	return g.parent.add(joinPath(g.prefix, path), method, version, e, g.policy(policy))
+}
+
+// policy returns the [TrailingSlash] policy of the group, fallback if the group
+// has none.
+func (g *Group) policy(fallback TrailingSlash) TrailingSlash {
+	if g.trailingSlash != nil {
+		return *g.trailingSlash
+	}
+	return fallback
+}
+
+func (g *Group) apply(e *Endpoint) {
+	names := make([]string, len(g.tags))
+	for i, t := range g.tags {
+		names[i] = t.Name
+	}
+	e.Tags = appendStrings(names, e.Tags...)
+	e.groupTags = appendTags(append([]Tag{}, g.tags...), e.groupTags...)
+	if e.Auth == nil {
+		e.Auth = g.auth
+	}
+	if e.ErrorResponse == nil && g.errorResponse != nil {
+		e.setErrorResponse(g.errorResponse)
+	}
+	e.groupMiddlewares = append(append([]Middleware{}, g.middlewares...), e.groupMiddlewares...)
+}
+
+// Mount adds all endpoints of other to c below the prefix provided. This allows
+// to compose collections built independently. The options provided apply to the
+// mounted endpoints just as to the endpoints of a [Group], use [WithMiddlewares]
+// to pass on the middlewares of the [Config] other is meant to be used with.
+// The endpoints of other are copied, other itself is left untouched and can be
+// mounted several times.
+func (c *Endpoints) Mount(prefix string, other Endpoints, opts ...GroupOption) error {
+	g := c.Group(prefix, opts...)
+	for caller, e := range other {
+		mounted := *e
+		mounted.Parameters = append([]Parameter{}, e.Parameters...)
+		mounted.groupTags = append([]Tag{}, e.groupTags...)
+		mounted.templates = nil
+		mounted.state = nil
+		tpl := other.template(caller)
+		if err := g.add(tpl.raw, caller.Method, caller.Version, &mounted, tpl.trailingSlash); err != nil {
+			return fmt.Errorf("cannot mount at '%s': %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+// Tags returns the tags of the groups the endpoints of the collection were
+// added to, sorted by name.
+func (c Endpoints) Tags() []Tag {
+	out := []Tag{}
+	for _, e := range c {
+		out = appendTags(out, e.groupTags...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// setErrorResponse sets the ErrorResponse of the endpoint and updates the
+// responses of the errors registered before.
+func (e *Endpoint) setErrorResponse(er ErrorResponse) {
+	e.ErrorResponse = er
+	for _, err := range e.registeredErrors {
+		if details, ok := e.Responses[err.Status].(string); ok && details == err.Details {
+			e.Responses[err.Status] = er
+		}
+	}
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func appendTags(tags []Tag, add ...Tag) []Tag {
+	for _, a := range add {
+		exists := false
+		for i, t := range tags {
+			if t.Name == a.Name {
+				exists = true
+				if t.Description == "" {
+					tags[i].Description = a.Description
+				}
+				break
+			}
+		}
+		if !exists {
+			tags = append(tags, a)
+		}
+	}
+	return tags
+}
+
+func appendStrings(list []string, add ...string) []string {
+	for _, a := range add {
+		exists := false
+		for _, l := range list {
+			if l == a {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, a)
+		}
+	}
+	return list
+}
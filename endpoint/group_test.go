@@ -0,0 +1,97 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	trace := []string{}
+	auth := &Auth{Name: "basic"}
+	endpoints := Endpoints{}
+	api := endpoints.Group("/api/v1",
+		WithTag("api", "The API"),
+		WithAuth(auth),
+		WithErrorResponse(testErrorResponse{}),
+		WithMiddlewares(recordingMiddleware("group", &trace)),
+	)
+	todos := api.Group("/todos/", WithTag("todos", "Manage todos"), WithMiddlewares(recordingMiddleware("nested", &trace)))
+
+	ep := &Endpoint{
+		Name:        "show",
+		Tags:        []string{"extra"},
+		Middlewares: []Middleware{recordingMiddleware("endpoint", &trace)},
+	}
+	errNotFound := ep.RegisterErrorValue(http.StatusNotFound, "not found")
+	ep.ErrHandler = func(w http.ResponseWriter, r *http.Request) error {
+		return errNotFound
+	}
+	if err := todos.Add("{name}", http.MethodGet, ep); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if _, ok := endpoints[Caller{Path: "/api/v1/todos/{name}/", Method: http.MethodGet}]; !ok {
+		t.Fatalf("endpoint is expected to be registered below the prefix of the group, have %v", endpoints)
+	}
+	if strings.Join(ep.Tags, ",") != "api,todos,extra" {
+		t.Errorf("tags are not as expected, have %v", ep.Tags)
+	}
+	if ep.Auth != auth {
+		t.Errorf("auth is expected to be inherited from the group")
+	}
+	if _, ok := ep.Responses[http.StatusNotFound].(testErrorResponse); !ok {
+		t.Errorf("registered errors are expected to be documented using the error response of the group, have %T", ep.Responses[http.StatusNotFound])
+	}
+	tags := endpoints.Tags()
+	if len(tags) != 2 || tags[1].Description != "Manage todos" {
+		t.Errorf("tags of the collection are not as expected, have %v", tags)
+	}
+
+	router := routerWithConfig(t, endpoints, Config{Middlewares: []Middleware{recordingMiddleware("global", &trace)}})
+	w := serve(router, httptest.NewRequest(http.MethodGet, "/api/v1/todos/x/", nil))
+	if w.Code != http.StatusNotFound || w.Body.String() != "error: not found" {
+		t.Errorf("response is not as expected, have %d %q", w.Code, w.Body.String())
+	}
+	expected := "global:show group:show nested:show endpoint:show"
+	if strings.Join(trace, " ") != expected {
+		t.Errorf("middlewares are not as expected, have %v, need %s", trace, expected)
+	}
+}
+
+func TestMount(t *testing.T) {
+	trace := []string{}
+	other := Endpoints{}
+	other.Group("/", WithTag("other", "Other endpoints")).Add("/items/{id | Item id}/", http.MethodGet, &Endpoint{
+		Name:    "item",
+		Handler: func(w http.ResponseWriter, r *http.Request) {},
+	})
+
+	endpoints := Endpoints{}
+	if err := endpoints.Mount("/api", other, WithMiddlewares(recordingMiddleware("other", &trace))); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := endpoints.Mount("/api", other); err == nil {
+		t.Errorf("mounting the same endpoints twice is expected to fail")
+	}
+	ep, ok := endpoints[Caller{Path: "/api/items/{id}/", Method: http.MethodGet}]
+	if !ok {
+		t.Fatalf("endpoint is expected to be mounted below the prefix, have %v", endpoints)
+	}
+	if len(ep.Parameters) != 1 || ep.Parameters[0].Description != "Item id" {
+		t.Errorf("parameters are not as expected, have %v", ep.Parameters)
+	}
+	if tags := endpoints.Tags(); len(tags) != 1 || tags[0].Name != "other" {
+		t.Errorf("tags are expected to be mounted as well, have %v", tags)
+	}
+	if len(other[Caller{Path: "/items/{id}/", Method: http.MethodGet}].groupMiddlewares) != 0 {
+		t.Errorf("mounted endpoints are expected to be left untouched")
+	}
+
+	router := routerWithConfig(t, endpoints, Config{Middlewares: []Middleware{recordingMiddleware("global", &trace)}})
+	serve(router, httptest.NewRequest(http.MethodGet, "/api/items/3/", nil))
+	if strings.Join(trace, " ") != "global:item other:item" {
+		t.Errorf("middlewares are not as expected, have %v", trace)
+	}
+}
@@ -0,0 +1,220 @@
+package endpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Idempotency makes retries of requests to an [Endpoint] safe according to
+// [draft-ietf-httpapi-idempotency-key-header]. Clients provide a unique key in
+// the 'Idempotency-Key' header. The first response for a key is stored and
+// replayed for all retries using the same key, without calling the Handler again.
+// Replayed responses carry the 'Idempotent-Replayed' header. Reusing a key with
+// a different request body is answered with '422 Unprocessable Entity', retries
+// while the first request is still being served with '409 Conflict', both via
+// the ErrorResponse of the endpoint. Server errors are not stored, which allows
+// clients to retry. The key is held until the Handler returns, even if the
+// request timed out in the meantime, the response of the Handler is then stored
+// and replayed to retries.
+//
+// [draft-ietf-httpapi-idempotency-key-header]: https://datatracker.ietf.org/doc/draft-ietf-httpapi-idempotency-key-header/
+type Idempotency struct {
+	// Store holds the responses, see [NewMemoryStore].
+	Store IdempotencyStore
+	// Required answers requests without 'Idempotency-Key' header with '400 Bad
+	// Request'. Such requests are served as usual if not set.
+	Required bool
+}
+
+// StoredResponse is a response held by an [IdempotencyStore].
+type StoredResponse struct {
+	// Fingerprint identifies the request the response was written for.
+	Fingerprint string
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore holds the responses of an endpoint with [Idempotency].
+// Keys are scoped to the method, path and principal of the request.
+type IdempotencyStore interface {
+	// Begin reserves the key for the request with the fingerprint provided. If a
+	// response is stored for the key, it is returned. False is returned if the key
+	// is reserved by a request in progress.
+	Begin(key, fingerprint string) (*StoredResponse, bool)
+	// Complete stores the response for a key reserved via Begin.
+	Complete(key string, response StoredResponse)
+	// Abort releases a key reserved via Begin without storing a response.
+	Abort(key string)
+}
+
+// NewMemoryStore returns an [IdempotencyStore] holding the responses in memory
+// for the time to live provided. Keys reserved by requests in progress are
+// released after the time to live as well, in case a request is never completed.
+// Responses are not shared between processes.
+func NewMemoryStore(ttl time.Duration) IdempotencyStore {
+	return newMemoryStore(ttl, time.Now)
+}
+
+func newMemoryStore(ttl time.Duration, now func() time.Time) *memoryStore {
+	return &memoryStore{ttl: ttl, now: now, entries: map[string]*memoryEntry{}, swept: now()}
+}
+
+type memoryStore struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	swept   time.Time
+}
+
+type memoryEntry struct {
+	response *StoredResponse
+	expires  time.Time
+}
+
+func (s *memoryStore) Begin(key, fingerprint string) (*StoredResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.now()
+	// entries expire when their key is used again, entries of keys which are
+	// not used again are dropped once per time to live
+	if t.Sub(s.swept) > s.ttl {
+		for k, entry := range s.entries {
+			if t.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.swept = t
+	}
+	if entry, ok := s.entries[key]; ok && !t.After(entry.expires) {
+		return entry.response, entry.response != nil
+	}
+	s.entries[key] = &memoryEntry{expires: t.Add(s.ttl)}
+	return nil, true
+}
+
+func (s *memoryStore) Complete(key string, response StoredResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &memoryEntry{response: &response, expires: s.now().Add(s.ttl)}
+}
+
+func (s *memoryStore) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// idempotency stores and replays the responses of the endpoint if enabled.
+func (e *Endpoint) idempotency(next http.HandlerFunc) http.HandlerFunc {
+	if e.Idempotency == nil || e.Idempotency.Store == nil {
+		return next
+	}
+	store := e.Idempotency.Store
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			if e.Idempotency.Required {
+				e.respondError(http.StatusBadRequest, "'Idempotency-Key' header is required", nil, w, r)
+				return
+			}
+			next(w, r)
+			return
+		}
+		raw, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			e.respondError(http.StatusRequestEntityTooLarge, bodyTooLarge(tooLarge.Limit), nil, w, r)
+			return
+		}
+		if err != nil {
+			e.respondError(http.StatusBadRequest, "request body could not be read", nil, w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		sum := sha256.Sum256(raw)
+		fingerprint := hex.EncodeToString(sum[:])
+		principal, _ := Principal(r)
+		scoped := r.Method + " " + r.URL.Path + " " + principal + " " + key
+
+		stored, ok := store.Begin(scoped, fingerprint)
+		switch {
+		case !ok:
+			e.respondError(http.StatusConflict, "a request with the same 'Idempotency-Key' is in progress", nil, w, r)
+			return
+		case stored != nil && stored.Fingerprint != fingerprint:
+			e.respondError(http.StatusUnprocessableEntity, "'Idempotency-Key' was used for a different request", nil, w, r)
+			return
+		case stored != nil:
+			for k, v := range stored.Header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.Status)
+			w.Write(stored.Body)
+			return
+		}
+
+		// only the headers written by the handler are stored, headers such as
+		// those of the rate limit are written for each request
+		before := w.Header().Clone()
+		rec := &recorder{ResponseWriter: w}
+		returned := false
+		defer func() {
+			// the key is released if the handler panics or fails
+			if !returned || rec.status == 0 || rec.status >= 500 {
+				store.Abort(scoped)
+				return
+			}
+			store.Complete(scoped, StoredResponse{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				Header:      headerDiff(before, w.Header()),
+				Body:        rec.body.Bytes(),
+			})
+		}()
+		next(rec, r)
+		returned = true
+	}
+}
+
+// recorder writes a response through while keeping a copy.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
+
+// headerDiff returns the headers of after which are not part of before.
+func headerDiff(before, after http.Header) http.Header {
+	out := http.Header{}
+	for k, v := range after {
+		if strings.Join(before[k], "\n") != strings.Join(v, "\n") {
+			out[k] = append([]string{}, v...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,188 @@
+package endpoint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		key      string
+		payload  string
+		status   int
+		response string
+		replayed bool
+	}
+	cases := map[string]struct {
+		required bool
+		requests []request
+	}{
+		"Retry replayed": {
+			requests: []request{
+				{key: "a", payload: "shopping", status: http.StatusCreated, response: "added shopping 1"},
+				{key: "a", payload: "shopping", status: http.StatusCreated, response: "added shopping 1", replayed: true},
+				{key: "b", payload: "shopping", status: http.StatusCreated, response: "added shopping 2"},
+			},
+		},
+		"Key reused with different payload": {
+			requests: []request{
+				{key: "a", payload: "shopping", status: http.StatusCreated, response: "added shopping 1"},
+				{key: "a", payload: "laundry", status: http.StatusUnprocessableEntity},
+			},
+		},
+		"Request without key": {
+			requests: []request{
+				{payload: "shopping", status: http.StatusCreated, response: "added shopping 1"},
+				{payload: "shopping", status: http.StatusCreated, response: "added shopping 2"},
+			},
+		},
+		"Key required": {
+			required: true,
+			requests: []request{
+				{payload: "shopping", status: http.StatusBadRequest},
+			},
+		},
+		"Server error not stored": {
+			requests: []request{
+				{key: "a", payload: "fail", status: http.StatusInternalServerError},
+				{key: "a", payload: "shopping", status: http.StatusCreated, response: "added shopping 2"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			e := &Endpoint{
+				ErrorResponse: testErrorResponse{},
+				Idempotency:   &Idempotency{Store: NewMemoryStore(time.Hour), Required: tc.required},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					calls++
+					raw, _ := io.ReadAll(r.Body)
+					payload := string(raw)
+					if payload == "fail" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.Header().Set("Location", "/todos/"+payload+"/")
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprintf(w, "added %s %d", payload, calls)
+				},
+			}
+			handler := e.handlerFunc()
+			for i, req := range tc.requests {
+				r := httptest.NewRequest(http.MethodPost, "/todos/", strings.NewReader(req.payload))
+				if req.key != "" {
+					r.Header.Set("Idempotency-Key", req.key)
+				}
+				w := httptest.NewRecorder()
+				handler(w, r)
+				if w.Code != req.status {
+					t.Errorf("status of request %d is not as expected, have %d, need %d", i, w.Code, req.status)
+				}
+				if req.response != "" && w.Body.String() != req.response {
+					t.Errorf("body of request %d is not as expected, have %q, need %q", i, w.Body.String(), req.response)
+				}
+				if replayed := w.Header().Get("Idempotent-Replayed") == "true"; replayed != req.replayed {
+					t.Errorf("request %d is expected to be replayed: %t", i, req.replayed)
+				}
+				if req.replayed && w.Header().Get("Location") != "/todos/"+req.payload+"/" {
+					t.Errorf("headers of request %d are expected to be replayed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	// keys are scoped to the method, path and principal of the request
+	store.Begin("POST /todos/  a", "")
+	e := &Endpoint{
+		ErrorResponse: testErrorResponse{},
+		Idempotency:   &Idempotency{Store: store},
+		Handler:       func(w http.ResponseWriter, r *http.Request) {},
+	}
+	r := httptest.NewRequest(http.MethodPost, "/todos/", nil)
+	r.Header.Set("Idempotency-Key", "a")
+	w := httptest.NewRecorder()
+	e.handlerFunc()(w, r)
+	if w.Code != http.StatusConflict {
+		t.Errorf("status is not as expected, have %d, need %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	store := newMemoryStore(time.Minute, func() time.Time { return clock })
+
+	if _, ok := store.Begin("a", "x"); !ok {
+		t.Fatalf("key is expected to be reserved")
+	}
+	if _, ok := store.Begin("a", "x"); ok {
+		t.Errorf("key is expected to be in progress")
+	}
+	clock = start.Add(2 * time.Minute)
+	if _, ok := store.Begin("a", "x"); !ok {
+		t.Errorf("key in progress is expected to be released after the time to live")
+	}
+	store.Complete("a", StoredResponse{Fingerprint: "x", Status: http.StatusCreated})
+	if stored, ok := store.Begin("a", "x"); !ok || stored == nil {
+		t.Errorf("response is expected to be stored")
+	}
+	clock = start.Add(4 * time.Minute)
+	if stored, ok := store.Begin("a", "x"); !ok || stored != nil {
+		t.Errorf("response is expected to expire after the time to live, have %v", stored)
+	}
+}
+
+// completingStore signals each response stored.
+type completingStore struct {
+	IdempotencyStore
+	completed chan struct{}
+}
+
+func (s completingStore) Complete(key string, response StoredResponse) {
+	s.IdempotencyStore.Complete(key, response)
+	s.completed <- struct{}{}
+}
+
+func TestIdempotencyTimeout(t *testing.T) {
+	release := make(chan struct{})
+	store := completingStore{IdempotencyStore: NewMemoryStore(time.Hour), completed: make(chan struct{}, 1)}
+	e := &Endpoint{
+		ErrorResponse: testErrorResponse{},
+		Idempotency:   &Idempotency{Store: store},
+		Timeout:       10 * time.Millisecond,
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		},
+	}
+	handler := e.handlerFunc()
+	call := func() int {
+		r := httptest.NewRequest(http.MethodPost, "/todos/", nil)
+		r.Header.Set("Idempotency-Key", "a")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w.Code
+	}
+
+	if status := call(); status != http.StatusGatewayTimeout {
+		t.Errorf("status of the first request is not as expected, have %d, need %d", status, http.StatusGatewayTimeout)
+	}
+	if status := call(); status != http.StatusConflict {
+		t.Errorf("key is expected to be held while the handler runs, have %d", status)
+	}
+	close(release)
+	<-store.completed
+	if status := call(); status != http.StatusCreated {
+		t.Errorf("response of the handler is expected to be replayed, have %d", status)
+	}
+}
@@ -0,0 +1,82 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Stability describes the maturity of an [Endpoint].
+type Stability string
+
+const (
+	StabilityAlpha Stability = "alpha" // the endpoint is experimental and may change or vanish at any time
+	StabilityBeta  Stability = "beta"  // the endpoint is feature complete but may still change
+	StabilityGA    Stability = "ga"    // the endpoint is generally available
+)
+
+// Lifecycle describes where an [Endpoint] stands in its lifecycle. Deprecated
+// endpoints are marked as such in the OpenAPI document and their responses carry
+// the 'Deprecation' header. According to [RFC 9745] the header holds the
+// DeprecatedSince date as '@<unix seconds>'. As the RFC does not define a value
+// without a date, 'true' as used by its drafts is sent if DeprecatedSince is not
+// set, so set it to send a compliant header. If a Sunset is set, responses
+// carry the 'Sunset' header according to [RFC 8594]. A Successor is announced via
+// the 'Link' header.
+//
+// [RFC 9745]: https://www.rfc-editor.org/rfc/rfc9745
+// [RFC 8594]: https://www.rfc-editor.org/rfc/rfc8594
+type Lifecycle struct {
+	// Deprecated marks the endpoint as deprecated.
+	Deprecated bool
+	// DeprecatedSince is the date the endpoint was deprecated at, sent in the
+	// 'Deprecation' header. Optional, but required for a header compliant with
+	// RFC 9745.
+	DeprecatedSince time.Time
+	// Sunset is the date the endpoint becomes unavailable at, optional.
+	Sunset time.Time
+	// Successor is the URI of the endpoint replacing this one, optional.
+	Successor string
+	// Stability is the stability stage of the endpoint, optional.
+	Stability Stability
+	// RejectAfterSunset answers requests with '410 Gone' via the ErrorResponse of
+	// the endpoint once the Sunset date has passed.
+	RejectAfterSunset bool
+}
+
+// sunsetPassed reports whether requests to the endpoint are rejected at the
+// time provided.
+func (l Lifecycle) sunsetPassed(t time.Time) bool {
+	return l.RejectAfterSunset && !l.Sunset.IsZero() && t.After(l.Sunset)
+}
+
+// lifecycle sets the lifecycle headers of the endpoint and rejects requests
+// once the sunset has passed if enabled.
+func (e *Endpoint) lifecycle(next http.HandlerFunc) http.HandlerFunc {
+	l := e.Lifecycle
+	if !l.Deprecated && l.Sunset.IsZero() && l.Successor == "" {
+		return next
+	}
+	state := e.sharedState()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if l.Deprecated {
+			deprecation := "true"
+			if !l.DeprecatedSince.IsZero() {
+				deprecation = fmt.Sprintf("@%d", l.DeprecatedSince.Unix())
+			}
+			w.Header().Set("Deprecation", deprecation)
+		}
+		if !l.Sunset.IsZero() {
+			w.Header().Set("Sunset", l.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if l.Successor != "" {
+			w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", l.Successor))
+		}
+		if l.sunsetPassed(state.time()) {
+			details := fmt.Sprintf("endpoint is no longer available since %s", l.Sunset.UTC().Format(time.RFC3339))
+			e.respondError(http.StatusGone, details, nil, w, r)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,76 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLifecycle(t *testing.T) {
+	clock := func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		lifecycle Lifecycle
+		status    int
+		headers   map[string]string
+		body      string
+	}{
+		"Generally available": {
+			lifecycle: Lifecycle{Stability: StabilityGA},
+			status:    http.StatusOK,
+			headers:   map[string]string{"Deprecation": "", "Sunset": "", "Link": ""},
+		},
+		"Deprecated with successor": {
+			lifecycle: Lifecycle{Deprecated: true, Successor: "/api/v2/todos/"},
+			status:    http.StatusOK,
+			headers: map[string]string{
+				"Deprecation": "true",
+				"Link":        `</api/v2/todos/>; rel="successor-version"`,
+			},
+		},
+		"Deprecated since with sunset ahead": {
+			lifecycle: Lifecycle{Deprecated: true, DeprecatedSince: since, Sunset: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), RejectAfterSunset: true},
+			status:    http.StatusOK,
+			headers: map[string]string{
+				"Deprecation": "@1704067200",
+				"Sunset":      "Tue, 31 Dec 2024 00:00:00 GMT",
+			},
+		},
+		"Sunset passed": {
+			lifecycle: Lifecycle{Deprecated: true, Sunset: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), RejectAfterSunset: true},
+			status:    http.StatusGone,
+			headers:   map[string]string{"Sunset": "Wed, 01 May 2024 00:00:00 GMT"},
+			body:      "error: endpoint is no longer available since 2024-05-01T00:00:00Z",
+		},
+		"Sunset passed without rejection": {
+			lifecycle: Lifecycle{Sunset: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			status:    http.StatusOK,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &Endpoint{
+				Lifecycle:     tc.lifecycle,
+				ErrorResponse: testErrorResponse{},
+				Handler:       func(w http.ResponseWriter, r *http.Request) {},
+				state:         &endpointState{now: clock},
+			}
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			for k, v := range tc.headers {
+				if have := w.Header().Get(k); have != v {
+					t.Errorf("header %s is not as expected, have %q, need %q", k, have, v)
+				}
+			}
+			if tc.body != "" && w.Body.String() != tc.body {
+				t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+			}
+		})
+	}
+}
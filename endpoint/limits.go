@@ -0,0 +1,181 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// limitBody limits the size of the request body to MaxBodyBytes. Requests
+// announcing a larger body are answered with '413 Content Too Large' right away,
+// reading beyond the limit fails for all others.
+func (e *Endpoint) limitBody(next http.HandlerFunc) http.HandlerFunc {
+	if e.MaxBodyBytes <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > e.MaxBodyBytes {
+			e.respondError(http.StatusRequestEntityTooLarge, bodyTooLarge(e.MaxBodyBytes), nil, w, r)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, e.MaxBodyBytes)
+		next(w, r)
+	}
+}
+
+func bodyTooLarge(limit int64) string {
+	return fmt.Sprintf("request body exceeds %d bytes", limit)
+}
+
+// limitInFlight answers requests with '503 Service Unavailable' while
+// MaxInFlight requests are being served by any of the routes of the endpoint.
+func (e *Endpoint) limitInFlight(next http.HandlerFunc) http.HandlerFunc {
+	if e.MaxInFlight <= 0 {
+		return next
+	}
+	state := e.sharedState()
+	state.slotsOnce.Do(func() { state.slots = make(chan struct{}, e.MaxInFlight) })
+	slots := state.slots
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+			next(w, r)
+		default:
+			w.Header().Set("Retry-After", "1")
+			e.respondError(http.StatusServiceUnavailable, "too many requests in flight", nil, w, r)
+		}
+	}
+}
+
+// timeout cancels the context of the request after Timeout and answers with
+// '504 Gateway Timeout' if the handler did not return by then, or with '503
+// Service Unavailable' if the request was cancelled by the client before. The
+// response of the handler is buffered in order to be discarded in these cases,
+// reading the request body fails from then on as the body is owned by the server
+// again once the request is answered.
+func (e *Endpoint) timeout(next http.HandlerFunc) http.HandlerFunc {
+	if e.Timeout <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), e.Timeout)
+		defer cancel()
+		tw := &timeoutWriter{header: http.Header{}}
+		body := &timeoutBody{body: r.Body}
+		r = r.WithContext(ctx)
+		r.Body = body
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next(tw, r)
+			close(done)
+		}()
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.flush(w)
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			body.close()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				e.respondError(http.StatusGatewayTimeout, fmt.Sprintf("request not served within %s", e.Timeout), nil, w, r)
+				return
+			}
+			e.respondError(http.StatusServiceUnavailable, "request cancelled", nil, w, r)
+		}
+	}
+}
+
+// timeoutBody guards the body of a request served with a timeout, it cannot be
+// read anymore once the request is answered.
+type timeoutBody struct {
+	mu     sync.Mutex
+	body   io.ReadCloser
+	closed bool
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+	return b.body.Read(p)
+}
+
+func (b *timeoutBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	return b.body.Close()
+}
+
+// close prevents the body from being read, reads in progress are waited for.
+func (b *timeoutBody) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+}
+
+// timeoutWriter buffers the response of a handler run with a timeout.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	return tw.body.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.status != 0 {
+		return
+	}
+	tw.status = status
+}
+
+// flush writes the buffered response to w.
+func (tw *timeoutWriter) flush(w http.ResponseWriter) {
+	for key, values := range tw.header {
+		w.Header()[key] = values
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	w.WriteHeader(tw.status)
+	w.Write(tw.body.Bytes())
+}
@@ -0,0 +1,192 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	type body struct {
+		Name string `json:"name"`
+	}
+	cases := map[string]struct {
+		endpoint Endpoint
+		body     string
+		chunked  bool
+		cancel   bool
+		status   int
+		response string
+	}{
+		"Body within limit": {
+			endpoint: Endpoint{MaxBodyBytes: 32},
+			body:     `{"name":"shopping"}`,
+			status:   http.StatusOK,
+			response: `{"name":"shopping"}`,
+		},
+		"Body too large": {
+			endpoint: Endpoint{MaxBodyBytes: 8},
+			body:     `{"name":"shopping"}`,
+			status:   http.StatusRequestEntityTooLarge,
+			response: "error: request body exceeds 8 bytes",
+		},
+		"Chunked body too large": {
+			endpoint: Endpoint{MaxBodyBytes: 8, RequestBody: body{}, DecodeBody: true},
+			body:     `{"name":"shopping"}`,
+			chunked:  true,
+			status:   http.StatusRequestEntityTooLarge,
+			response: "error: request body is invalid: body '.' request body exceeds 8 bytes",
+		},
+		"Chunked body too large with timeout": {
+			endpoint: Endpoint{MaxBodyBytes: 8, Timeout: time.Second, RequestBody: body{}, DecodeBody: true},
+			body:     `{"name":"shopping"}`,
+			chunked:  true,
+			status:   http.StatusRequestEntityTooLarge,
+			response: "error: request body is invalid: body '.' request body exceeds 8 bytes",
+		},
+		"Within timeout": {
+			endpoint: Endpoint{Timeout: time.Second},
+			body:     "fast",
+			status:   http.StatusOK,
+			response: "fast",
+		},
+		"Timeout exceeded": {
+			endpoint: Endpoint{Timeout: 10 * time.Millisecond},
+			body:     "slow",
+			status:   http.StatusGatewayTimeout,
+			response: "error: request not served within 10ms",
+		},
+		"Request cancelled before timeout": {
+			endpoint: Endpoint{Timeout: time.Second},
+			body:     "slow",
+			cancel:   true,
+			status:   http.StatusServiceUnavailable,
+			response: "error: request cancelled",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := tc.endpoint
+			e.ErrorResponse = testErrorResponse{}
+			e.Handler = func(w http.ResponseWriter, r *http.Request) {
+				raw, err := io.ReadAll(r.Body)
+				if err != nil {
+					e.respondError(http.StatusRequestEntityTooLarge, bodyTooLarge(e.MaxBodyBytes), nil, w, r)
+					return
+				}
+				if string(raw) == "slow" {
+					<-r.Context().Done()
+				}
+				w.Write(raw)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			if tc.chunked {
+				r.ContentLength = -1
+			}
+			if tc.cancel {
+				ctx, cancel := context.WithCancel(r.Context())
+				cancel()
+				r = r.WithContext(ctx)
+			}
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, r)
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			if w.Body.String() != tc.response {
+				t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.response)
+			}
+		})
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	e := &Endpoint{
+		MaxInFlight:   1,
+		ErrorResponse: testErrorResponse{},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			fmt.Fprint(w, "done")
+		},
+	}
+	handler := e.handlerFunc()
+
+	first, done := httptest.NewRecorder(), make(chan struct{})
+	go func() {
+		handler(first, httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+	<-started
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status is not as expected, have %d, need %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Errorf("Retry-After header is expected to be set")
+	}
+	close(release)
+	<-done
+	if first.Body.String() != "done" {
+		t.Errorf("first request is expected to be served, have %q", first.Body.String())
+	}
+}
+
+func TestMaxInFlightSharedByRoutes(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	endpoints := Endpoints{}
+	endpoints.Add("/todos/", http.MethodGet, &Endpoint{
+		MaxInFlight:   1,
+		ErrorResponse: testErrorResponse{},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		},
+	})
+	handler := routerWithEndpoints(t, endpoints)
+
+	done := make(chan struct{})
+	go func() {
+		serve(handler, httptest.NewRequest(http.MethodGet, "/todos/", nil))
+		close(done)
+	}()
+	<-started
+	w := serve(handler, httptest.NewRequest(http.MethodHead, "/todos/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status is not as expected, have %d, need %d", w.Code, http.StatusServiceUnavailable)
+	}
+	close(release)
+	<-done
+}
+
+func TestTimeoutBody(t *testing.T) {
+	answered, read := make(chan struct{}), make(chan error)
+	e := &Endpoint{
+		Timeout:       10 * time.Millisecond,
+		ErrorResponse: testErrorResponse{},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			<-answered
+			_, err := io.ReadAll(r.Body)
+			read <- err
+		},
+	}
+	w := httptest.NewRecorder()
+	e.handlerFunc()(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("late")))
+	close(answered)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status is not as expected, have %d, need %d", w.Code, http.StatusGatewayTimeout)
+	}
+	if err := <-read; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Errorf("reading the body after the timeout is expected to fail, have %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Automatic returns the endpoints answered automatically by the collection
+// unless DisableAutoMethods of the [Config] provided is set:
+//
+//   - HEAD for every path with a GET endpoint, served by the GET endpoint. The
+//     response body is discarded by the [net/http.Server].
+//   - OPTIONS for every path, answered with '204 No Content' and an 'Allow'
+//     header listing the methods of the path.
+//
+// Methods explicitly added to a path are never overwritten. Requests using any
+// other method are answered with '405 Method Not Allowed', see [Endpoints.Populate].
+func (c Endpoints) Automatic(cfg Config) Endpoints {
+	out := Endpoints{}
+	if cfg.DisableAutoMethods {
+		return out
+	}
+	for caller, get := range c {
+		if caller.Method != http.MethodGet {
+			continue
+		}
+		head := caller
+		head.Method = http.MethodHead
+		if _, ok := c[head]; ok {
+			continue
+		}
+		e := *get
+		e.Description = fmt.Sprintf("%s (headers only)", get.Description)
+		out[head] = &e
+	}
+	for path, methods := range c.methods() {
+		if !contains(methods, http.MethodOptions) {
+			out[Caller{Path: path, Method: http.MethodOptions}] = c.optionsEndpoint(path, cfg)
+		}
+	}
+	return out
+}
+
+// Allowed returns the methods requests to the path provided are answered for,
+// including the methods answered automatically according to the [Config]
+// provided.
+func (c Endpoints) Allowed(path string, cfg Config) []string {
+	methods := c.methods()[path]
+	out := append([]string{}, methods...)
+	if !cfg.DisableAutoMethods && len(methods) > 0 {
+		if contains(methods, http.MethodGet) && !contains(methods, http.MethodHead) {
+			out = append(out, http.MethodHead)
+		}
+		if !contains(methods, http.MethodOptions) {
+			out = append(out, http.MethodOptions)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// methods returns the methods of all endpoints in the collection by path,
+// regardless of their version.
+func (c Endpoints) methods() map[string][]string {
+	out := map[string][]string{}
+	for caller := range c {
+		if !contains(out[caller.Path], caller.Method) {
+			out[caller.Path] = append(out[caller.Path], caller.Method)
+		}
+	}
+	return out
+}
+
+// representative returns the endpoint which describes the path as a whole, used
+// to render errors and to document the methods answered automatically.
+func (c Endpoints) representative(path string) *Endpoint {
+	var rep *Endpoint
+	for _, caller := range c.callersOf(path) {
+		rep = c[caller]
+		break
+	}
+	if rep == nil {
+		return &Endpoint{}
+	}
+	return rep
+}
+
+func (c Endpoints) optionsEndpoint(path string, cfg Config) *Endpoint {
+	rep := c.representative(path)
+	hidden := true
+	for caller, e := range c {
+		if caller.Path == path && !e.Hidden {
+			hidden = false
+		}
+	}
+	e := &Endpoint{
+		Name:          "options",
+		Description:   fmt.Sprintf("Methods allowed for %s", path),
+		Responses:     map[int]interface{}{http.StatusNoContent: ""},
+		ErrorResponse: rep.ErrorResponse,
+		Tags:          rep.Tags,
+		Hidden:        hidden,
+	}
+	allow := strings.Join(c.Allowed(path, cfg), ", ")
+	e.Handler = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return e
+}
+
+// fallback returns the handler answering the requests no route matches.
+// Requests to a path of the collection using a method the path does not allow
+// are answered with '405 Method Not Allowed'. The 'Allow' header lists the
+// methods of all paths matching, the error is rendered by the endpoints of the
+// most specific one. Requests to a path of the collection with path parameters
+// not matching their constraints are answered with '404 Not Found' rendered by
+// the endpoints of the path. All other requests are answered with a plain
+// '404 Not Found'.
+func (c Endpoints) fallback(cfg Config) http.HandlerFunc {
+	type candidate struct {
+		tpl        pathTemplate
+		match      *regexp.Regexp
+		shape      *regexp.Regexp
+		allowed    []string
+		notAllowed http.HandlerFunc
+		notFound   http.HandlerFunc
+	}
+	candidates := []candidate{}
+	alternates := c.alternatePaths()
+	for path, tpl := range c.templates() {
+		_, alternate := alternates[path]
+		rep := c.representative(path)
+		notAllowed := func(w http.ResponseWriter, r *http.Request) {
+			rep.respondError(http.StatusMethodNotAllowed, "method not allowed", nil, w, r)
+		}
+		notFound := func(w http.ResponseWriter, r *http.Request) {
+			rep.respondError(http.StatusNotFound, "not found", nil, w, r)
+		}
+		candidates = append(candidates, candidate{
+			tpl:        tpl,
+			match:      tpl.matcher(true, alternate),
+			shape:      tpl.matcher(false, alternate),
+			allowed:    c.Allowed(path, cfg),
+			notAllowed: wrap(*rep, notAllowed, cfg.Middlewares),
+			notFound:   wrap(*rep, notFound, cfg.Middlewares),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].tpl.moreSpecific(candidates[j].tpl)
+	})
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed := []string{}
+		var handler http.HandlerFunc
+		for _, cand := range candidates {
+			if !cand.match.MatchString(r.URL.Path) {
+				continue
+			}
+			for _, method := range cand.allowed {
+				if !contains(allowed, method) {
+					allowed = append(allowed, method)
+				}
+			}
+			if handler == nil {
+				handler = cand.notAllowed
+			}
+		}
+		if handler != nil {
+			sort.Strings(allowed)
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			handler(w, r)
+			return
+		}
+		for _, cand := range candidates {
+			if cand.shape.MatchString(r.URL.Path) {
+				cand.notFound(w, r)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
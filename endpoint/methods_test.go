@@ -0,0 +1,113 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAutoMethods(t *testing.T) {
+	cases := map[string]struct {
+		disabled bool
+		method   string
+		path     string
+		status   int
+		allow    string
+		body     string
+	}{
+		"Head": {
+			method: http.MethodHead,
+			path:   "/todos/",
+			status: http.StatusOK,
+		},
+		"Options": {
+			method: http.MethodOptions,
+			path:   "/todos/",
+			status: http.StatusNoContent,
+			allow:  "GET, HEAD, OPTIONS, POST",
+		},
+		"Options without get": {
+			method: http.MethodOptions,
+			path:   "/todos/x/",
+			status: http.StatusNoContent,
+			allow:  "DELETE, OPTIONS",
+		},
+		"Method not allowed": {
+			method: http.MethodDelete,
+			path:   "/todos/",
+			status: http.StatusMethodNotAllowed,
+			allow:  "GET, HEAD, OPTIONS, POST",
+			body:   "error: method not allowed",
+		},
+		"Head not allowed": {
+			method: http.MethodHead,
+			path:   "/todos/x/",
+			status: http.StatusMethodNotAllowed,
+			allow:  "DELETE, OPTIONS",
+		},
+		"Options disabled": {
+			disabled: true,
+			method:   http.MethodOptions,
+			path:     "/todos/",
+			status:   http.StatusMethodNotAllowed,
+			allow:    "GET, POST",
+			body:     "error: method not allowed",
+		},
+		"Method not allowed with automatic methods disabled": {
+			disabled: true,
+			method:   http.MethodDelete,
+			path:     "/todos/",
+			status:   http.StatusMethodNotAllowed,
+			allow:    "GET, POST",
+			body:     "error: method not allowed",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			endpoints := Endpoints{}
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "ok")
+			}
+			endpoints.Add("/todos/", http.MethodGet, &Endpoint{ErrorResponse: testErrorResponse{}, Handler: handler})
+			endpoints.Add("/todos/", http.MethodPost, &Endpoint{ErrorResponse: testErrorResponse{}, Handler: handler})
+			endpoints.Add("/todos/{name}/", http.MethodDelete, &Endpoint{ErrorResponse: testErrorResponse{}, Handler: handler})
+			router := routerWithConfig(t, endpoints, Config{DisableAutoMethods: tc.disabled})
+
+			w := serve(router, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			if allow := w.Header().Get("Allow"); allow != tc.allow {
+				t.Errorf("allow header is not as expected, have %q, need %q", allow, tc.allow)
+			}
+			if tc.body != "" && w.Body.String() != tc.body {
+				t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+			}
+		})
+	}
+}
+
+func TestAutomatic(t *testing.T) {
+	endpoints := Endpoints{}
+	endpoints.Add("/todos/", http.MethodGet, &Endpoint{Name: "list", Description: "List todos", Tags: []string{"todos"}})
+	endpoints.Add("/todos/", http.MethodOptions, &Endpoint{Name: "custom options"})
+	endpoints.Add("/todos/{name}/", http.MethodPut, &Endpoint{Name: "update", Hidden: true})
+
+	auto := endpoints.Automatic(Config{})
+	if len(auto) != 2 {
+		t.Fatalf("automatic endpoints are not as expected, have %v", auto)
+	}
+	head, ok := auto[Caller{Path: "/todos/", Method: http.MethodHead}]
+	if !ok || head.Name != "list" || head.Description != "List todos (headers only)" {
+		t.Errorf("head endpoint is not as expected, have %v", head)
+	}
+	options, ok := auto[Caller{Path: "/todos/{name}/", Method: http.MethodOptions}]
+	if !ok || !options.Hidden {
+		t.Errorf("options endpoint is expected to be hidden if all endpoints of the path are hidden, have %v", options)
+	}
+	if auto := endpoints.Automatic(Config{DisableAutoMethods: true}); len(auto) != 0 {
+		t.Errorf("no endpoints are expected to be answered automatically if disabled, have %v", auto)
+	}
+}
@@ -0,0 +1,39 @@
+package endpoint
+
+import "net/http"
+
+// Middleware wraps the handler of an [Endpoint]. Just as the [AuthMiddlewareInjector]
+// it receives the [Endpoint] it wraps, which allows to make use of the meta data of
+// the [Endpoint], for example to label metrics with its name or to apply limits
+// to specific operations only.
+//
+// Middlewares are applied by [Endpoints.Populate] in a well defined order,
+// from the outermost to the innermost:
+//
+//  1. the Middlewares of the [Config] of the collection
+//  2. the Middlewares of the [Group] the [Endpoint] was added to
+//  3. the Middlewares of the [Endpoint] itself
+//  4. the MiddlewareInjector of the [Auth] of the [Endpoint]
+//  5. the Handler of the [Endpoint]
+//
+// Within a list, the first Middleware is the outermost one.
+type Middleware func(Endpoint, http.HandlerFunc) http.HandlerFunc
+
+// chain returns the handler of the endpoint wrapped in all middlewares which
+// apply to the endpoint.
+func (cfg Config) chain(e *Endpoint) http.HandlerFunc {
+	handler := e.handlerFunc()
+	if e.Auth != nil && e.Auth.MiddlewareInjector != nil {
+		handler = e.Auth.MiddlewareInjector(*e, handler)
+	}
+	handler = wrap(*e, handler, e.Middlewares)
+	handler = wrap(*e, handler, e.groupMiddlewares)
+	return wrap(*e, handler, cfg.Middlewares)
+}
+
+func wrap(e Endpoint, handler http.HandlerFunc, middlewares []Middleware) http.HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](e, handler)
+	}
+	return handler
+}
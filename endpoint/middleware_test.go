@@ -0,0 +1,47 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func recordingMiddleware(label string, trace *[]string) Middleware {
+	return func(e Endpoint, next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, label+":"+e.Name)
+			next(w, r)
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	trace := []string{}
+	ep := &Endpoint{
+		Name: "test",
+		Auth: &Auth{
+			MiddlewareInjector: AuthMiddlewareInjector(recordingMiddleware("auth", &trace)),
+		},
+		Middlewares: []Middleware{
+			recordingMiddleware("endpoint1", &trace),
+			recordingMiddleware("endpoint2", &trace),
+		},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			trace = append(trace, "handler")
+		},
+	}
+	var endpoints Endpoints
+	if err := endpoints.Add("/", http.MethodGet, ep); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	router := routerWithConfig(t, endpoints, Config{
+		Middlewares: []Middleware{recordingMiddleware("global", &trace)},
+	})
+	serve(router, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := []string{"global:test", "endpoint1:test", "endpoint2:test", "auth:test", "handler"}
+	if strings.Join(trace, " ") != strings.Join(expected, " ") {
+		t.Errorf("order is not as expected, have %v, need %v", trace, expected)
+	}
+}
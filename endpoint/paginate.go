@@ -0,0 +1,222 @@
+package endpoint
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/invopop/jsonschema"
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+// PaginationMode defines how the pages of a list are addressed.
+type PaginationMode int
+
+const (
+	PaginationOffset PaginationMode = iota // pages are addressed by the 'offset' and 'limit' query parameters
+	PaginationCursor                       // pages are addressed by the 'cursor' and the 'limit' query parameters, see [Pagination]
+)
+
+// Envelope defines the names of the fields of the object a page is wrapped in.
+// Fields left empty use the default names 'items', 'next', 'prev' and 'total'.
+type Envelope struct {
+	// Items holds the items of the page.
+	Items string
+	// Next and Prev hold the URLs of the next and previous page if any.
+	Next, Prev string
+	// Total holds the number of items of the list.
+	Total string
+}
+
+// Pagination declares that an [Endpoint] returns a list in pages. Use
+// [Endpoint.Paginate] to add pagination to an endpoint.
+//
+// The cursors of [PaginationCursor] are meant to be opaque to clients, but they
+// only encode the offset of the page. They are not stable keys: items added or
+// removed in front of a page shift its content just as with [PaginationOffset].
+type Pagination struct {
+	// Mode defines how pages are addressed, see [PaginationMode].
+	Mode PaginationMode
+	// DefaultLimit is the number of items per page if the request does not
+	// provide a limit, defaults to 20. It is capped at MaxLimit.
+	DefaultLimit int
+	// MaxLimit is the maximum number of items per page, defaults to 100.
+	MaxLimit int
+	// Envelope defines the names of the fields of the page.
+	Envelope Envelope
+}
+
+// Page addresses a page of a list as requested by a client.
+type Page struct {
+	Limit, Offset int
+}
+
+// Paginate declares pagination on the endpoint. The 'limit' as well as the
+// 'offset' or 'cursor' query parameters are added to the Parameters of the
+// endpoint. Lists returned by a handler created via [Typed] are paginated
+// automatically, other handlers use [Endpoint.RespondPage].
+func (e *Endpoint) Paginate(p Pagination) {
+	if p.MaxLimit <= 0 {
+		p.MaxLimit = 100
+	}
+	if p.DefaultLimit <= 0 {
+		p.DefaultLimit = 20
+	}
+	p.DefaultLimit = min(p.DefaultLimit, p.MaxLimit)
+	minLimit, maxLimit := 1.0, float64(p.MaxLimit)
+	e.Parameters = append(e.Parameters, Parameter{
+		Name:        "limit",
+		Location:    ParameterLocationQuery,
+		Type:        ParameterTypeInteger,
+		Default:     fmt.Sprint(p.DefaultLimit),
+		Description: "Maximum number of items per page",
+		Minimum:     &minLimit,
+		Maximum:     &maxLimit,
+	})
+	if p.Mode == PaginationCursor {
+		e.Parameters = append(e.Parameters, Parameter{
+			Name:        "cursor",
+			Location:    ParameterLocationQuery,
+			Type:        ParameterTypeString,
+			Description: "Opaque cursor of the page as provided by the 'next' or 'prev' link",
+		})
+	} else {
+		zero := 0.0
+		e.Parameters = append(e.Parameters, Parameter{
+			Name:        "offset",
+			Location:    ParameterLocationQuery,
+			Type:        ParameterTypeInteger,
+			Default:     "0",
+			Description: "Number of items to skip",
+			Minimum:     &zero,
+		})
+	}
+	e.Pagination = &p
+}
+
+// FieldNames returns the names of the fields of the envelope with the defaults
+// applied in the order items, next, prev and total.
+func (env Envelope) FieldNames() (items, next, prev, total string) {
+	or := func(name, fallback string) string {
+		if name == "" {
+			return fallback
+		}
+		return name
+	}
+	return or(env.Items, "items"), or(env.Next, "next"), or(env.Prev, "prev"), or(env.Total, "total")
+}
+
+// envelopeSchema wraps the schema of the items in the schema of the envelope of
+// a page as published in the OpenAPI document.
+func (p Pagination) envelopeSchema(items *jsonschema.Schema) *jsonschema.Schema {
+	itemsField, nextField, prevField, totalField := p.Envelope.FieldNames()
+	inner := *items
+	inner.Definitions = nil
+	props := orderedmap.New()
+	props.Set(itemsField, &inner)
+	props.Set(nextField, &jsonschema.Schema{Type: "string", Format: "uri-reference"})
+	props.Set(prevField, &jsonschema.Schema{Type: "string", Format: "uri-reference"})
+	props.Set(totalField, &jsonschema.Schema{Type: "integer"})
+	return &jsonschema.Schema{Type: "object", Properties: props, Definitions: items.Definitions}
+}
+
+// Page returns the page requested. An error is returned if the parameters of
+// the request are invalid.
+func (e *Endpoint) Page(r *http.Request) (Page, error) {
+	if e.Pagination == nil {
+		return Page{}, fmt.Errorf("endpoint '%s' is not paginated", e.Name)
+	}
+	out := Page{Limit: e.Pagination.DefaultLimit}
+	limit, _ := e.parameterByName("limit")
+	vals, ok, err := limit.Values(r)
+	if err != nil {
+		return out, Violations{{Field: "limit", Location: "query", Message: err.Error()}}
+	}
+	if ok && len(vals) > 0 {
+		out.Limit, _ = strconv.Atoi(vals[0])
+	}
+	if e.Pagination.Mode == PaginationCursor {
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "" {
+			return out, nil
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		offset, convErr := strconv.Atoi(strings.TrimPrefix(string(raw), "offset:"))
+		if err != nil || convErr != nil || offset < 0 {
+			return out, Violations{{Field: "cursor", Location: "query", Message: "is not a valid cursor"}}
+		}
+		out.Offset = offset
+		return out, nil
+	}
+	offset, _ := e.parameterByName("offset")
+	vals, ok, err = offset.Values(r)
+	if err != nil {
+		return out, Violations{{Field: "offset", Location: "query", Message: err.Error()}}
+	}
+	if ok && len(vals) > 0 {
+		out.Offset, _ = strconv.Atoi(vals[0])
+	}
+	return out, nil
+}
+
+// RespondPage responds with the page requested of the list of items provided,
+// which must be a slice. The page is wrapped in the [Envelope] of the endpoint
+// and rendered using [respond.Auto]. Links to the next and previous page are
+// provided in the 'Link' header according to [RFC 8288] as well as in the
+// envelope. Invalid page parameters are answered with '400 Bad Request' via the
+// ErrorResponse of the endpoint.
+//
+// [RFC 8288]: https://www.rfc-editor.org/rfc/rfc8288
+func (e *Endpoint) RespondPage(w http.ResponseWriter, r *http.Request, items interface{}) error {
+	list := reflect.ValueOf(items)
+	if list.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot paginate %T, a slice is required", items)
+	}
+	page, err := e.Page(r)
+	if err != nil {
+		e.respondError(http.StatusBadRequest, "request parameters are invalid", err, w, r)
+		return nil
+	}
+	total := list.Len()
+	start, end := min(page.Offset, total), min(page.Offset+page.Limit, total)
+
+	itemsField, nextField, prevField, totalField := e.Pagination.Envelope.FieldNames()
+	env := map[string]interface{}{
+		itemsField: list.Slice(start, end).Interface(),
+		totalField: total,
+	}
+	links := []string{}
+	if end < total {
+		next := e.pageURL(r, Page{Limit: page.Limit, Offset: end})
+		env[nextField] = next
+		links = append(links, fmt.Sprintf("<%s>; rel=\"next\"", next))
+	}
+	if start > 0 {
+		prev := e.pageURL(r, Page{Limit: page.Limit, Offset: max(start-page.Limit, 0)})
+		env[prevField] = prev
+		links = append(links, fmt.Sprintf("<%s>; rel=\"prev\"", prev))
+	}
+	if len(links) > 0 {
+		w.Header().Add("Link", strings.Join(links, ", "))
+	}
+	return respond.Auto(w, r, http.StatusOK, env)
+}
+
+// pageURL returns the URL of the request addressing the page provided.
+func (e *Endpoint) pageURL(r *http.Request, page Page) string {
+	u := *r.URL
+	query := u.Query()
+	query.Set("limit", fmt.Sprint(page.Limit))
+	if e.Pagination.Mode == PaginationCursor {
+		query.Set("cursor", base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", page.Offset))))
+	} else {
+		query.Set("offset", fmt.Sprint(page.Offset))
+	}
+	u.RawQuery = query.Encode()
+	u.Scheme, u.Host = "", ""
+	return u.String()
+}
@@ -0,0 +1,131 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	cases := map[string]struct {
+		pagination Pagination
+		query      string
+		status     int
+		page       map[string]interface{}
+		link       string
+	}{
+		"Default page": {
+			pagination: Pagination{DefaultLimit: 2},
+			status:     http.StatusOK,
+			page:       map[string]interface{}{"items": []interface{}{1.0, 2.0}, "total": 5.0, "next": "/todos/?limit=2&offset=2"},
+			link:       `</todos/?limit=2&offset=2>; rel="next"`,
+		},
+		"Page at offset": {
+			pagination: Pagination{},
+			query:      "?limit=2&offset=2",
+			status:     http.StatusOK,
+			page: map[string]interface{}{
+				"items": []interface{}{3.0, 4.0}, "total": 5.0,
+				"next": "/todos/?limit=2&offset=4", "prev": "/todos/?limit=2&offset=0",
+			},
+			link: `</todos/?limit=2&offset=4>; rel="next", </todos/?limit=2&offset=0>; rel="prev"`,
+		},
+		"Last page": {
+			pagination: Pagination{},
+			query:      "?limit=2&offset=4",
+			status:     http.StatusOK,
+			page:       map[string]interface{}{"items": []interface{}{5.0}, "total": 5.0, "prev": "/todos/?limit=2&offset=2"},
+			link:       `</todos/?limit=2&offset=2>; rel="prev"`,
+		},
+		"Offset beyond the end": {
+			pagination: Pagination{},
+			query:      "?offset=10",
+			status:     http.StatusOK,
+			page:       map[string]interface{}{"items": []interface{}{}, "total": 5.0, "prev": "/todos/?limit=20&offset=0"},
+			link:       `</todos/?limit=20&offset=0>; rel="prev"`,
+		},
+		"Page at cursor": {
+			pagination: Pagination{Mode: PaginationCursor, Envelope: Envelope{Items: "data", Total: "count"}},
+			query:      "?limit=3&cursor=b2Zmc2V0OjE",
+			status:     http.StatusOK,
+			page: map[string]interface{}{
+				"data": []interface{}{2.0, 3.0, 4.0}, "count": 5.0,
+				"next": "/todos/?cursor=b2Zmc2V0OjQ&limit=3", "prev": "/todos/?cursor=b2Zmc2V0OjA&limit=3",
+			},
+			link: `</todos/?cursor=b2Zmc2V0OjQ&limit=3>; rel="next", </todos/?cursor=b2Zmc2V0OjA&limit=3>; rel="prev"`,
+		},
+		"Invalid cursor": {
+			pagination: Pagination{Mode: PaginationCursor},
+			query:      "?cursor=invalid",
+			status:     http.StatusBadRequest,
+		},
+		"Limit exceeded": {
+			pagination: Pagination{MaxLimit: 3},
+			query:      "?limit=4",
+			status:     http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := Typed(func(ctx context.Context, in struct{}) ([]int, error) {
+				return items, nil
+			})
+			e.Paginate(tc.pagination)
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, httptest.NewRequest(http.MethodGet, "/todos/"+tc.query, nil))
+			if w.Code != tc.status {
+				t.Fatalf("status is not as expected, have %d, need %d: %s", w.Code, tc.status, w.Body.String())
+			}
+			if tc.page == nil {
+				return
+			}
+			page := map[string]interface{}{}
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatalf("page could not be decoded: %s", err)
+			}
+			if !reflect.DeepEqual(page, tc.page) {
+				t.Errorf("page is not as expected, have %v, need %v", page, tc.page)
+			}
+			if link := w.Header().Get("Link"); link != tc.link {
+				t.Errorf("link header is not as expected, have %q, need %q", link, tc.link)
+			}
+		})
+	}
+}
+
+func TestPaginateDefaults(t *testing.T) {
+	cases := map[string]struct {
+		pagination   Pagination
+		defaultLimit string
+	}{
+		"Zero value":                        {defaultLimit: "20"},
+		"Max limit below the default":       {pagination: Pagination{MaxLimit: 5}, defaultLimit: "5"},
+		"Default limit above the max limit": {pagination: Pagination{DefaultLimit: 50, MaxLimit: 10}, defaultLimit: "10"},
+		"Default limit within the bounds":   {pagination: Pagination{DefaultLimit: 50}, defaultLimit: "50"},
+		"Negative limits":                   {pagination: Pagination{DefaultLimit: -1, MaxLimit: -1}, defaultLimit: "20"},
+		"Cursor with max limit of one":      {pagination: Pagination{Mode: PaginationCursor, MaxLimit: 1}, defaultLimit: "1"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &Endpoint{}
+			e.Paginate(tc.pagination)
+			for _, param := range e.Parameters {
+				if param.Name == "limit" && param.Default != tc.defaultLimit {
+					t.Errorf("default limit is not as expected, have %s, need %s", param.Default, tc.defaultLimit)
+				}
+				if param.Default == "" {
+					continue
+				}
+				if err := param.Validate(param.Default); err != nil {
+					t.Errorf("default '%s' of parameter '%s' is invalid: %s", param.Default, param.Name, err)
+				}
+			}
+		})
+	}
+}
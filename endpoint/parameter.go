@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
-
-	"github.com/gorilla/mux"
+	"sync"
 )
 
 // Parameter represents the [Parameter Object] of the [OpenAPI Specification]. It also cames
@@ -21,6 +21,172 @@ type Parameter struct {
 	Default     string            `json:"default" yaml:"default"`
 	Description string            `json:"description" yaml:"description"`
 	Type        string            `json:"content" yaml:"content"`
+	// Format further specifies the Type, for example 'uuid', 'date' or 'date-time'.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Enum lists the values allowed.
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	// Minimum and Maximum restrict numeric values, they are ignored for
+	// parameters of other types.
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	// Pattern is a regular expression string values must match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Items is the type of the items if Type is 'array'. All other constraints
+	// apply to the items.
+	Items string `json:"items,omitempty" yaml:"items,omitempty"`
+	// Style describes how arrays are serialized, see [ParameterStyle].
+	Style ParameterStyle `json:"style,omitempty" yaml:"style,omitempty"`
+	// Deprecated marks the parameter as deprecated.
+	Deprecated bool `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+// Types which can be used as Type or Items of a [Parameter].
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeArray   = "array"
+)
+
+// ParameterStyle describes how array values are serialized according to the
+// [Style Values] of the OpenAPI Specification. If no style is provided, 'form'
+// is used for query and cookie parameters, 'simple' is used for path and header
+// parameters.
+//
+// [Style Values]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#styleValues
+type ParameterStyle string
+
+const (
+	ParameterStyleForm           ParameterStyle = "form"           // values are provided as repeated parameters, e.g. 'id=3&id=4'
+	ParameterStyleSimple         ParameterStyle = "simple"         // values are comma separated, e.g. '3,4'
+	ParameterStyleSpaceDelimited ParameterStyle = "spaceDelimited" // values are space separated, e.g. '3 4'
+	ParameterStylePipeDelimited  ParameterStyle = "pipeDelimited"  // values are pipe separated, e.g. '3|4'
+)
+
+// EffectiveStyle returns the Style of the parameter or the default style of its
+// location if no style is provided.
+func (p Parameter) EffectiveStyle() ParameterStyle {
+	if p.Style != "" {
+		return p.Style
+	}
+	if p.Location == ParameterLocationQuery || p.Location == ParameterLocationCookie {
+		return ParameterStyleForm
+	}
+	return ParameterStyleSimple
+}
+
+// Values does the same as Get but also splits array values according to the
+// Style of the parameter and validates all values using Validate.
+func (p Parameter) Values(r *http.Request) ([]string, bool, error) {
+	vals, ok := p.Get(r)
+	if !ok {
+		return vals, false, nil
+	}
+	vals = p.split(vals)
+	for _, v := range vals {
+		if err := p.Validate(v); err != nil {
+			return vals, true, err
+		}
+	}
+	return vals, true, nil
+}
+
+// split splits array values according to the Style of the parameter.
+func (p Parameter) split(vals []string) []string {
+	if p.Type != ParameterTypeArray {
+		return vals
+	}
+	sep := map[ParameterStyle]string{
+		ParameterStyleSimple:         ",",
+		ParameterStyleSpaceDelimited: " ",
+		ParameterStylePipeDelimited:  "|",
+	}[p.EffectiveStyle()]
+	if sep == "" {
+		return vals
+	}
+	out := []string{}
+	for _, v := range vals {
+		out = append(out, strings.Split(v, sep)...)
+	}
+	return out
+}
+
+// Validate checks a single value against the schema of the parameter. For
+// parameters of Type 'array' the value is checked against the Items type.
+func (p Parameter) Validate(val string) error {
+	typ := p.Type
+	if typ == ParameterTypeArray {
+		typ = p.Items
+	}
+	var num float64
+	numeric := false
+	switch typ {
+	case ParameterTypeInteger:
+		i, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		num, numeric = float64(i), true
+	case ParameterTypeNumber:
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		num, numeric = f, true
+	case ParameterTypeBoolean:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+	}
+	if numeric && p.Minimum != nil && num < *p.Minimum {
+		return fmt.Errorf("must be greater than or equal to %v", *p.Minimum)
+	}
+	if numeric && p.Maximum != nil && num > *p.Maximum {
+		return fmt.Errorf("must be less than or equal to %v", *p.Maximum)
+	}
+	if len(p.Enum) > 0 {
+		found := false
+		for _, e := range p.Enum {
+			if e == val {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("must be one of %s", strings.Join(p.Enum, ", "))
+		}
+	}
+	if p.Pattern != "" {
+		rex, err := compilePattern(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("cannot be checked against invalid pattern '%s'", p.Pattern)
+		}
+		if !rex.MatchString(val) {
+			return fmt.Errorf("must match pattern '%s'", p.Pattern)
+		}
+	}
+	if check, ok := formats[p.Format]; ok && !check(val) {
+		return fmt.Errorf("must be a valid %s", p.Format)
+	}
+	return nil
+}
+
+// patterns caches the regular expressions of parameters and schemas.
+var patterns sync.Map
+
+// compilePattern returns the regular expression of the pattern, each pattern is
+// compiled once only.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if rex, ok := patterns.Load(pattern); ok {
+		return rex.(*regexp.Regexp), nil
+	}
+	rex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, rex)
+	return rex, nil
 }
 
 // Get returns values if a given [http.Request]. To do this it respects the
@@ -28,7 +194,7 @@ type Parameter struct {
 // the Defalut will be returned.
 func (p Parameter) Get(r *http.Request) ([]string, bool) {
 	if p.Location == ParameterLocationPath {
-		v, ok := mux.Vars(r)[p.Name]
+		v, ok := pathValue(r, p.Name)
 		if !ok && p.Default != "" {
 			return []string{p.Default}, true
 		} else if !ok {
@@ -97,32 +263,3 @@ var parameterLocationText = map[ParameterLocation]string{
 func (l ParameterLocation) String() string {
 	return parameterLocationText[l]
 }
-
-func extractPathParams(path string) (tidy string, params []Parameter) {
-	tidy = path
-	rex := regexp.MustCompile(`\{(?P<param>.*)\}`)
-	matches := rex.FindAllStringSubmatch(path, -1)
-	for _, param := range matches {
-		if len(param) < 2 {
-			continue
-		}
-		whole := param[0]
-		pair := strings.SplitN(param[1], "|", 2)
-		key := strings.TrimSpace(pair[0])
-		desc := key
-		if len(pair) == 2 {
-			desc = strings.TrimSpace(pair[1])
-		}
-		tidy = strings.ReplaceAll(tidy, whole, fmt.Sprintf("{%s}", key))
-		p := Parameter{
-			Name:        key,
-			Location:    ParameterLocationPath,
-			Required:    true,
-			Default:     "",
-			Description: desc,
-			Type:        "string",
-		}
-		params = append(params, p)
-	}
-	return
-}
@@ -2,8 +2,12 @@ package endpoint
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"sort"
 	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/mux"
 )
 
 func TestParameterGet(t *testing.T) {
@@ -196,6 +200,122 @@ func TestParameterGet(t *testing.T) {
 	}
 }
 
+func TestParameterValues(t *testing.T) {
+	lower, upper := 1.0, 10.0
+	cases := map[string]struct {
+		request      *http.Request
+		param        Parameter
+		expectedVals []string
+		expectedOK   bool
+		expectedErr  bool
+	}{
+		"Integer in range": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"limit": {"5"}}),
+			param:        Parameter{Name: "limit", Type: ParameterTypeInteger, Minimum: &lower, Maximum: &upper},
+			expectedVals: []string{"5"},
+			expectedOK:   true,
+		},
+		"Integer out of range": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"limit": {"11"}}),
+			param:        Parameter{Name: "limit", Type: ParameterTypeInteger, Minimum: &lower, Maximum: &upper},
+			expectedVals: []string{"11"},
+			expectedOK:   true,
+			expectedErr:  true,
+		},
+		"String with bounds": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"name": {"abc"}}),
+			param:        Parameter{Name: "name", Type: ParameterTypeString, Minimum: &lower, Maximum: &upper},
+			expectedVals: []string{"abc"},
+			expectedOK:   true,
+		},
+		"Not an integer": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"limit": {"ten"}}),
+			param:        Parameter{Name: "limit", Type: ParameterTypeInteger},
+			expectedVals: []string{"ten"},
+			expectedOK:   true,
+			expectedErr:  true,
+		},
+		"Enum": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"state": {"closed"}}),
+			param:        Parameter{Name: "state", Type: ParameterTypeString, Enum: []string{"open", "done"}},
+			expectedVals: []string{"closed"},
+			expectedOK:   true,
+			expectedErr:  true,
+		},
+		"Format": {
+			request:      requestWithHeaderParameters(nil, map[string][]string{"X-Id": {"not-a-uuid"}}),
+			param:        Parameter{Name: "X-Id", Location: ParameterLocationHeader, Type: ParameterTypeString, Format: "uuid"},
+			expectedVals: []string{"not-a-uuid"},
+			expectedOK:   true,
+			expectedErr:  true,
+		},
+		"Array in form style": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"id": {"3", "4"}}),
+			param:        Parameter{Name: "id", Type: ParameterTypeArray, Items: ParameterTypeInteger},
+			expectedVals: []string{"3", "4"},
+			expectedOK:   true,
+		},
+		"Array in pipe delimited style": {
+			request:      requestWithQueryParameters(nil, map[string][]string{"id": {"3|4"}}),
+			param:        Parameter{Name: "id", Type: ParameterTypeArray, Items: ParameterTypeInteger, Style: ParameterStylePipeDelimited},
+			expectedVals: []string{"3", "4"},
+			expectedOK:   true,
+		},
+		"Array in simple style with invalid item": {
+			request:      requestWithHeaderParameters(nil, map[string][]string{"X-Id": {"3,four"}}),
+			param:        Parameter{Name: "X-Id", Location: ParameterLocationHeader, Type: ParameterTypeArray, Items: ParameterTypeInteger},
+			expectedVals: []string{"3", "four"},
+			expectedOK:   true,
+			expectedErr:  true,
+		},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			vals, ok, err := c.param.Values(c.request)
+			if ok != c.expectedOK {
+				t.Errorf("ok is not as expected, have %t, need %t", ok, c.expectedOK)
+			}
+			if (err != nil) != c.expectedErr {
+				t.Errorf("error is not as expected, have %v, need error: %t", err, c.expectedErr)
+			}
+			if !equal(vals, c.expectedVals) {
+				t.Errorf("values not as expected, have %v, need %v", vals, c.expectedVals)
+			}
+		})
+	}
+}
+
+func TestGetParamAs(t *testing.T) {
+	e := &Endpoint{Parameters: []Parameter{
+		{Name: "done", Type: ParameterTypeBoolean},
+		{Name: "ratio", Type: ParameterTypeNumber},
+		{Name: "since", Type: ParameterTypeString, Format: "date"},
+		{Name: "tag", Type: ParameterTypeArray, Items: ParameterTypeString, Enum: []string{"a", "b"}},
+	}}
+	r := requestWithQueryParameters(nil, map[string][]string{
+		"done":  {"true"},
+		"ratio": {"0.5"},
+		"since": {"2022-10-01"},
+		"tag":   {"a", "b"},
+	})
+	if v, ok := e.GetParamAsBool("done", r); !ok || !v {
+		t.Errorf("bool is not as expected, have %t, %t", v, ok)
+	}
+	if v, ok := e.GetParamAsFloat("ratio", r); !ok || v != 0.5 {
+		t.Errorf("float is not as expected, have %f, %t", v, ok)
+	}
+	if v, ok := e.GetParamAsTime("since", r); !ok || v.Month() != 10 {
+		t.Errorf("time is not as expected, have %s, %t", v, ok)
+	}
+	if v, ok := e.GetParamAsStrings("tag", r); !ok || !equal(v, []string{"a", "b"}) {
+		t.Errorf("strings are not as expected, have %v, %t", v, ok)
+	}
+	r = requestWithQueryParameters(nil, map[string][]string{"tag": {"a", "c"}})
+	if v, ok := e.GetParamAsStrings("tag", r); ok {
+		t.Errorf("values not matching the schema are not expected to be returned, have %v", v)
+	}
+}
+
 func requestWithNoParameters() *http.Request {
 	r, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
 	return r
@@ -248,6 +368,51 @@ func equal(a, b []string) bool {
 	return true
 }
 
+func routerWithEndpoints(t *testing.T, endpoints Endpoints) http.Handler {
+	t.Helper()
+	return routerWithConfig(t, endpoints, Config{})
+}
+
+func routerWithConfig(t *testing.T, endpoints Endpoints, cfg Config) http.Handler {
+	t.Helper()
+	router := mux.NewRouter()
+	endpoints.Populate(MuxRouter(router), cfg)
+	return router
+}
+
+func routerWithEndpoint(t *testing.T, path, method string, e *Endpoint) http.Handler {
+	t.Helper()
+	endpoints := Endpoints{}
+	if err := endpoints.Add(path, method, e); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return routerWithEndpoints(t, endpoints)
+}
+
+// routers returns a constructor for each of the routers supported by name.
+func routers() map[string]func() (http.Handler, Router) {
+	return map[string]func() (http.Handler, Router){
+		"Gorilla mux": func() (http.Handler, Router) {
+			r := mux.NewRouter()
+			return r, MuxRouter(r)
+		},
+		"ServeMux": func() (http.Handler, Router) {
+			r := http.NewServeMux()
+			return r, ServeMuxRouter(r)
+		},
+		"Chi": func() (http.Handler, Router) {
+			r := chi.NewRouter()
+			return r, ChiRouter(r)
+		},
+	}
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
 /*
 type Parameter struct {
 	Name        string            `json:"name" yaml:"name"`
@@ -0,0 +1,238 @@
+package endpoint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pathConstraint describes a type constraint of a path parameter such as '{id:int}'.
+type pathConstraint struct {
+	typ     string
+	format  string
+	pattern string
+}
+
+var pathConstraints = map[string]pathConstraint{
+	"int":    {typ: ParameterTypeInteger, pattern: `-?[0-9]+`},
+	"number": {typ: ParameterTypeNumber, pattern: `-?[0-9]+(\.[0-9]+)?`},
+	"bool":   {typ: ParameterTypeBoolean, pattern: `(true|false)`},
+	"uuid":   {typ: ParameterTypeString, format: "uuid", pattern: `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`},
+}
+
+var pathParamName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pathTemplate is the parsed form of a path passed to [Endpoints.Add].
+type pathTemplate struct {
+	// raw is the path as passed to [Endpoints.Add], versioned paths hold the
+	// [VersionPlaceholder]
+	raw string
+	// path is the path without constraints and descriptions, used in the [Caller]
+	path string
+	// params are the path parameters found in the path
+	params []Parameter
+	// patterns hold the regular expressions the path parameters must match
+	patterns map[string]string
+	// prefix is the fixed part of the path in front of the catch-all segment
+	prefix string
+	// catchAll is the name of the catch-all parameter
+	catchAll string
+	// isPrefix is true if the path ends with a catch-all segment
+	isPrefix bool
+	// trailingSlash is the policy the path was canonicalized with
+	trailingSlash TrailingSlash
+	// versionByPath is true if the path holds the version of the endpoint
+	versionByPath bool
+}
+
+// parsePath parses a path template. Path parameters are written as
+//
+//	{name[:constraint][ | description]}
+//
+// where constraint is either one of 'int', 'number', 'bool' and 'uuid' or a
+// regular expression. Alternatives in a regular expression must be grouped in
+// parentheses as the first '|' outside of parentheses and brackets starts the
+// description. A parameter written as '{name...}' is a catch-all parameter which
+// matches the remainder of the path, including slashes. A path ending with '*/'
+// or '*' matches all paths below without capturing the remainder.
+func parsePath(path string) (pathTemplate, error) {
+	tpl := pathTemplate{raw: path, patterns: map[string]string{}}
+	tidy := strings.Builder{}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '}':
+			return tpl, fmt.Errorf("path '%s' has an unexpected '}' at position %d", path, i)
+		case '{':
+			end := closingBrace(path, i)
+			if end < 0 {
+				return tpl, fmt.Errorf("path '%s' has an unclosed '{' at position %d", path, i)
+			}
+			p, pattern, catchAll, err := parsePathParam(path[i+1 : end])
+			if err != nil {
+				return tpl, fmt.Errorf("path '%s' is invalid: %w", path, err)
+			}
+			for _, existing := range tpl.params {
+				if existing.Name == p.Name {
+					return tpl, fmt.Errorf("path '%s' defines parameter '%s' more than once", path, p.Name)
+				}
+			}
+			tpl.params = append(tpl.params, p)
+			if pattern != "" {
+				tpl.patterns[p.Name] = pattern
+			}
+			if catchAll {
+				if rest := path[end+1:]; rest != "" && rest != "/" {
+					return tpl, fmt.Errorf("path '%s' is invalid: catch-all parameter '%s' must be the last segment", path, p.Name)
+				}
+				tpl.prefix = tidy.String()
+				tpl.catchAll = p.Name
+				tpl.isPrefix = true
+				tidy.WriteString("{" + p.Name + "...}")
+				i = len(path)
+				continue
+			}
+			tidy.WriteString("{" + p.Name + "}")
+			i = end
+		case '*':
+			if path[i:] != "*/" && path[i:] != "*" {
+				tidy.WriteByte(path[i])
+				continue
+			}
+			tpl.prefix = tidy.String()
+			tpl.isPrefix = true
+			tidy.WriteString("*/")
+			i = len(path)
+		default:
+			tidy.WriteByte(path[i])
+		}
+	}
+	tpl.path = tidy.String()
+	return tpl, nil
+}
+
+// parsePathParam parses the part of a path parameter within the braces.
+func parsePathParam(spec string) (p Parameter, pattern string, catchAll bool, err error) {
+	def, desc := splitDescription(spec)
+	name, constraint := def, ""
+	if i := strings.Index(def, ":"); i >= 0 {
+		name, constraint = def[:i], def[i+1:]
+	}
+	name, constraint = strings.TrimSpace(name), strings.TrimSpace(constraint)
+	if strings.HasSuffix(name, "...") {
+		name = strings.TrimSuffix(name, "...")
+		catchAll = true
+	}
+	if !pathParamName.MatchString(name) {
+		return p, "", false, fmt.Errorf("'%s' is not a valid parameter name", name)
+	}
+	if desc == "" {
+		desc = name
+	}
+	p = Parameter{
+		Name:        name,
+		Location:    ParameterLocationPath,
+		Required:    true,
+		Default:     "",
+		Description: desc,
+		Type:        ParameterTypeString,
+	}
+	if constraint == "" {
+		return p, "", catchAll, nil
+	}
+	if catchAll {
+		return p, "", false, fmt.Errorf("catch-all parameter '%s' cannot be constrained", name)
+	}
+	if c, ok := pathConstraints[constraint]; ok {
+		p.Type = c.typ
+		p.Format = c.format
+		return p, c.pattern, false, nil
+	}
+	if _, err := regexp.Compile(constraint); err != nil {
+		return p, "", false, fmt.Errorf("constraint of parameter '%s' is not a valid regular expression: %w", name, err)
+	}
+	p.Pattern = "^" + constraint + "$"
+	return p, constraint, false, nil
+}
+
+// splitDescription splits the definition of a path parameter from its description
+// at the first '|' which is not part of a group or character class.
+func splitDescription(spec string) (def, desc string) {
+	depth := 0
+	for i, c := range spec {
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '|':
+			if depth == 0 {
+				return strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+			}
+		}
+	}
+	return strings.TrimSpace(spec), ""
+}
+
+// closingBrace returns the index of the brace closing the one at start. Braces
+// used as quantifiers in regular expressions are allowed within.
+func closingBrace(path string, start int) int {
+	depth := 0
+	for i := start; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// route returns the [Route] of the template for the method provided.
+func (t pathTemplate) route(method string) Route {
+	r := Route{Method: method, Path: t.path, CatchAll: t.catchAll}
+	if t.isPrefix {
+		r.Path = t.prefix
+		r.Prefix = true
+	}
+	if len(t.patterns) > 0 {
+		r.Patterns = t.patterns
+	}
+	return r
+}
+
+// tidyParam matches the path parameters of a path without constraints and
+// descriptions such as the path of a [Caller].
+var tidyParam = regexp.MustCompile(`\{([^}]*)\}`)
+
+// matcher returns a regular expression matching the request paths the template
+// is routed for. The constraints of the path parameters are ignored unless
+// constrained is set. If alternate is set, the non-canonical form of the path
+// regarding its trailing slash is matched as well.
+func (t pathTemplate) matcher(constrained, alternate bool) *regexp.Regexp {
+	path := t.path
+	if t.isPrefix {
+		path = t.prefix
+	}
+	expr := ""
+	last := 0
+	for _, loc := range tidyParam.FindAllStringSubmatchIndex(path, -1) {
+		expr += regexp.QuoteMeta(path[last:loc[0]])
+		if pattern, ok := t.patterns[path[loc[2]:loc[3]]]; ok && constrained {
+			expr += "(?:" + pattern + ")"
+		} else {
+			expr += "[^/]+"
+		}
+		last = loc[1]
+	}
+	expr += regexp.QuoteMeta(path[last:])
+	if t.isPrefix {
+		expr += ".*"
+	} else if alternate {
+		expr = strings.TrimSuffix(expr, "/") + "/?"
+	}
+	return regexp.MustCompile("^" + expr + "$")
+}
@@ -0,0 +1,136 @@
+package endpoint
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		tidy     string
+		params   []Parameter
+		patterns map[string]string
+		prefix   string
+		catchAll string
+		err      bool
+	}{
+		"Static path": {
+			path:     "/todos/",
+			tidy:     "/todos/",
+			patterns: map[string]string{},
+		},
+		"Multiple parameters": {
+			path: "/orgs/{org | Org}/repos/{repo | Repo}/",
+			tidy: "/orgs/{org}/repos/{repo}/",
+			params: []Parameter{
+				{Name: "org", Location: ParameterLocationPath, Required: true, Description: "Org", Type: ParameterTypeString},
+				{Name: "repo", Location: ParameterLocationPath, Required: true, Description: "Repo", Type: ParameterTypeString},
+			},
+			patterns: map[string]string{},
+		},
+		"Type constraint": {
+			path: "/todos/{id:int | Todo id}/",
+			tidy: "/todos/{id}/",
+			params: []Parameter{
+				{Name: "id", Location: ParameterLocationPath, Required: true, Description: "Todo id", Type: ParameterTypeInteger},
+			},
+			patterns: map[string]string{"id": pathConstraints["int"].pattern},
+		},
+		"Regular expression constraint": {
+			path: "/items/{code:[A-Z]{3}}/{kind:(a|b) | Kind}/",
+			tidy: "/items/{code}/{kind}/",
+			params: []Parameter{
+				{Name: "code", Location: ParameterLocationPath, Required: true, Description: "code", Type: ParameterTypeString, Pattern: "^[A-Z]{3}$"},
+				{Name: "kind", Location: ParameterLocationPath, Required: true, Description: "Kind", Type: ParameterTypeString, Pattern: "^(a|b)$"},
+			},
+			patterns: map[string]string{"code": "[A-Z]{3}", "kind": "(a|b)"},
+		},
+		"Catch all parameter": {
+			path: "/files/{bucket}/{path... | File path}/",
+			tidy: "/files/{bucket}/{path...}",
+			params: []Parameter{
+				{Name: "bucket", Location: ParameterLocationPath, Required: true, Description: "bucket", Type: ParameterTypeString},
+				{Name: "path", Location: ParameterLocationPath, Required: true, Description: "File path", Type: ParameterTypeString},
+			},
+			patterns: map[string]string{},
+			prefix:   "/files/{bucket}/",
+			catchAll: "path",
+		},
+		"Wildcard suffix": {
+			path:     "/static/*/",
+			tidy:     "/static/*/",
+			patterns: map[string]string{},
+			prefix:   "/static/",
+		},
+		"Unclosed brace": {
+			path: "/todos/{id/",
+			err:  true,
+		},
+		"Unopened brace": {
+			path: "/todos/id}/",
+			err:  true,
+		},
+		"Duplicate parameter": {
+			path: "/{id}/{id}/",
+			err:  true,
+		},
+		"Invalid name": {
+			path: "/{ | Nameless}/",
+			err:  true,
+		},
+		"Invalid regular expression": {
+			path: "/{id:[0-9}/",
+			err:  true,
+		},
+		"Catch all not last": {
+			path: "/{path...}/more/",
+			err:  true,
+		},
+		"Constrained catch all": {
+			path: "/{path...:int}/",
+			err:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tpl, err := parsePath(tc.path)
+			if tc.err {
+				if err == nil {
+					t.Errorf("error expected, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if tpl.path != tc.tidy {
+				t.Errorf("path is not as expected, have %q, need %q", tpl.path, tc.tidy)
+			}
+			if !reflect.DeepEqual(tpl.params, tc.params) {
+				t.Errorf("params are not as expected, have %v, need %v", tpl.params, tc.params)
+			}
+			if !reflect.DeepEqual(tpl.patterns, tc.patterns) {
+				t.Errorf("patterns are not as expected, have %v, need %v", tpl.patterns, tc.patterns)
+			}
+			if tpl.prefix != tc.prefix || tpl.catchAll != tc.catchAll {
+				t.Errorf("prefix is not as expected, have %q %q, need %q %q", tpl.prefix, tpl.catchAll, tc.prefix, tc.catchAll)
+			}
+		})
+	}
+}
+
+func TestAddMalformedPath(t *testing.T) {
+	endpoints := Endpoints{}
+	if err := endpoints.Add("/todos/{id/", http.MethodGet, &Endpoint{}); err == nil {
+		t.Errorf("malformed path is expected to be reported")
+	}
+	if err := endpoints.Add("/todos/{id:int}/", http.MethodGet, &Endpoint{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := endpoints.Add("/todos/{id:uuid}/", http.MethodPut, &Endpoint{}); err == nil {
+		t.Errorf("conflicting constraints are expected to be reported")
+	}
+}
@@ -0,0 +1,57 @@
+package endpoint
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+// Preconditions enable optimistic concurrency control for an [Endpoint]. PUT,
+// PATCH and DELETE requests must provide the entity tag of the resource they
+// modify in the 'If-Match' header. Requests without 'If-Match' header are answered
+// with '428 Precondition Required', requests providing an outdated entity tag with
+// '412 Precondition Failed', both via the ErrorResponse of the endpoint. GET and
+// HEAD requests providing the current entity tag in the 'If-None-Match' header are
+// answered with '304 Not Modified' without calling the Handler. Use
+// [github.com/unprofession-al/httpthings/respond.Conditional] to set the 'ETag'
+// header of the responses of the Handler.
+type Preconditions struct {
+	// ETag returns the current strong entity tag of the resource targeted by the
+	// request and false if the resource does not exist.
+	ETag func(r *http.Request) (string, bool)
+}
+
+// RequiresMatch reports whether requests using the method must provide the
+// 'If-Match' header.
+func (p Preconditions) RequiresMatch(method string) bool {
+	return method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// preconditions evaluates the conditional headers of the request if the
+// endpoint opted in.
+func (e *Endpoint) preconditions(next http.HandlerFunc) http.HandlerFunc {
+	if e.Preconditions == nil || e.Preconditions.ETag == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		etag, exists := e.Preconditions.ETag(r)
+		switch {
+		case e.Preconditions.RequiresMatch(r.Method):
+			ifMatch := r.Header.Get("If-Match")
+			if ifMatch == "" {
+				e.respondError(http.StatusPreconditionRequired, "'If-Match' header is required", nil, w, r)
+				return
+			}
+			if !exists || !respond.MatchETag(ifMatch, etag, true) {
+				e.respondError(http.StatusPreconditionFailed, "resource has been modified", nil, w, r)
+				return
+			}
+		case exists && respond.NotModified(r, etag, time.Time{}):
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,123 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+func TestPreconditions(t *testing.T) {
+	todo := map[string]string{"name": "shopping"}
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	current := func() string {
+		w := httptest.NewRecorder()
+		respond.JSON(w, http.StatusOK, todo)
+		return respond.ETag(w.Body.Bytes())
+	}
+
+	cases := map[string]struct {
+		method  string
+		headers map[string]string
+		missing bool
+		status  int
+		etag    bool
+	}{
+		"Get without conditions": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			etag:   true,
+		},
+		"Get with current entity tag": {
+			method:  http.MethodGet,
+			headers: map[string]string{"If-None-Match": `"outdated", ` + current()},
+			status:  http.StatusNotModified,
+			etag:    true,
+		},
+		"Get with outdated entity tag": {
+			method:  http.MethodGet,
+			headers: map[string]string{"If-None-Match": `"outdated"`},
+			status:  http.StatusOK,
+			etag:    true,
+		},
+		"Get not modified since": {
+			method:  http.MethodGet,
+			headers: map[string]string{"If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat)},
+			status:  http.StatusNotModified,
+		},
+		"Get modified since": {
+			method:  http.MethodGet,
+			headers: map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			status:  http.StatusOK,
+		},
+		"Put with current entity tag": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-Match": current()},
+			status:  http.StatusNoContent,
+		},
+		"Put with wildcard": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-Match": "*"},
+			status:  http.StatusNoContent,
+		},
+		"Put with wildcard to missing resource": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-Match": "*"},
+			missing: true,
+			status:  http.StatusPreconditionFailed,
+		},
+		"Put with outdated entity tag": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-Match": `"outdated"`},
+			status:  http.StatusPreconditionFailed,
+		},
+		"Put with weak entity tag": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-Match": "W/" + current()},
+			status:  http.StatusPreconditionFailed,
+		},
+		"Delete without if-match": {
+			method: http.MethodDelete,
+			status: http.StatusPreconditionRequired,
+		},
+		"Post without conditions": {
+			method: http.MethodPost,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &Endpoint{
+				ErrorResponse: testErrorResponse{},
+				Preconditions: &Preconditions{ETag: func(r *http.Request) (string, bool) {
+					return current(), !tc.missing
+				}},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet {
+						respond.Conditional(w, r, http.StatusOK, todo, modified)
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				},
+			}
+			r := httptest.NewRequest(tc.method, "/todos/shopping/", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			e.handlerFunc()(w, r)
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			if tc.etag && w.Header().Get("ETag") != current() {
+				t.Errorf("ETag is not as expected, have %q, need %q", w.Header().Get("ETag"), current())
+			}
+			if w.Code == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("body of a '304 Not Modified' response is expected to be empty, have %q", w.Body.String())
+			}
+		})
+	}
+}
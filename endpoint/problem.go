@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+// MediaTyper can be implemented by values used as RequestBody or in the Responses
+// of an [Endpoint] which are not rendered as 'application/json'. The media type
+// returned is then used when generating the OpenAPI document.
+type MediaTyper interface {
+	MediaType() string
+}
+
+// ProblemDetails is an [ErrorResponse] which renders errors as [RFC 9457] problem
+// details. Use it as a template: Type and Extensions set on the ErrorResponse of
+// an [Endpoint] are used for all errors of that [Endpoint], Title, Status, Detail
+// and Instance are filled in for every error if not provided. Depending on the
+// 'accept' header of the request, the problem is rendered as JSON or YAML.
+//
+// [RFC 9457]: https://www.rfc-editor.org/rfc/rfc9457
+type ProblemDetails struct {
+	// Type is a URI reference that identifies the problem type, defaults to 'about:blank'.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Title is a short, human-readable summary of the problem type, defaults to the status text.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status,omitempty" yaml:"status,omitempty"`
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+	// Instance is a URI reference that identifies this occurrence, defaults to the request path.
+	Instance string `json:"instance,omitempty" yaml:"instance,omitempty"`
+	// Errors holds the field level violations which caused the problem.
+	Errors Violations `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Extensions are additional members rendered next to the members above.
+	Extensions map[string]interface{} `json:"-" yaml:"-"`
+}
+
+// Respond implements [ErrorResponse].
+func (p ProblemDetails) Respond(status int, details string, w http.ResponseWriter, r *http.Request) {
+	p.RespondViolations(status, details, nil, w, r)
+}
+
+// RespondViolations implements [ViolationResponse].
+func (p ProblemDetails) RespondViolations(status int, details string, violations Violations, w http.ResponseWriter, r *http.Request) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(status)
+	}
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	p.Status = status
+	p.Detail = details
+	p.Errors = violations
+	contentType := respond.ContentTypeProblemJSON
+	if respond.AcceptsYAML(r) {
+		contentType = respond.ContentTypeProblemYAML
+	}
+	respond.Auto(w, r, status, p, map[string]string{"Content-Type": contentType})
+}
+
+// MediaType implements [MediaTyper].
+func (p ProblemDetails) MediaType() string {
+	return respond.ContentTypeProblemJSON
+}
+
+// MarshalJSON renders the Extensions as members of the problem details object.
+// Members defined by RFC 9457 cannot be overwritten by Extensions.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	type alias ProblemDetails
+	raw, err := json.Marshal(alias(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return raw, err
+	}
+	members := map[string]interface{}{}
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
@@ -0,0 +1,60 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+func TestProblemDetails(t *testing.T) {
+	template := ProblemDetails{
+		Type:       "https://example.com/problems/todo",
+		Extensions: map[string]interface{}{"traceId": "abc", "status": 999},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/todos/", nil)
+	w := httptest.NewRecorder()
+	template.RespondViolations(http.StatusUnprocessableEntity, "request body is invalid",
+		Violations{{Field: "name", Location: "body", Message: "is required"}}, w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status is not as expected, have %d, need %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != respond.ContentTypeProblemJSON {
+		t.Errorf("content type is not as expected, have %s, need %s", ct, respond.ContentTypeProblemJSON)
+	}
+	have := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &have); err != nil {
+		t.Fatalf("could not unmarshal response: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		"type":     "https://example.com/problems/todo",
+		"title":    "Unprocessable Entity",
+		"status":   float64(http.StatusUnprocessableEntity),
+		"detail":   "request body is invalid",
+		"instance": "/todos/",
+		"traceId":  "abc",
+	}
+	for k, v := range expected {
+		if have[k] != v {
+			t.Errorf("member '%s' is not as expected, have %v, need %v", k, have[k], v)
+		}
+	}
+	if errs, ok := have["errors"].([]interface{}); !ok || len(errs) != 1 {
+		t.Errorf("errors are not as expected, have %v", have["errors"])
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/todos/x/", nil)
+	r.Header.Set("Accept", "application/problem+yaml")
+	w = httptest.NewRecorder()
+	ProblemDetails{}.Respond(http.StatusNotFound, "todo not found", w, r)
+	if ct := w.Header().Get("Content-Type"); ct != respond.ContentTypeProblemYAML {
+		t.Errorf("content type is not as expected, have %s, need %s", ct, respond.ContentTypeProblemYAML)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "title: Not Found") || !strings.Contains(body, "type: about:blank") {
+		t.Errorf("yaml body is not as expected, have %q", body)
+	}
+}
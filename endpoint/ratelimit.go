@@ -0,0 +1,161 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the number of requests a client can send to an [Endpoint].
+// Requests are counted per client in a token bucket holding up to Burst tokens,
+// which is refilled at a rate of Requests per Window. Responses carry the
+// 'RateLimit-Limit', 'RateLimit-Remaining', 'RateLimit-Reset' and 'RateLimit-Policy'
+// headers according to [draft-ietf-httpapi-ratelimit-headers]. Requests exceeding
+// the limit are answered with '429 Too Many Requests' via the ErrorResponse of
+// the endpoint along with a 'Retry-After' header.
+//
+// The limit is enforced in process. The buckets are kept per endpoint and are
+// shared by all routes registered for it via [Endpoints.Populate], such as the
+// HEAD route answered automatically and the routes of its versions.
+//
+// [draft-ietf-httpapi-ratelimit-headers]: https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+type RateLimit struct {
+	// Requests is the number of requests allowed per Window.
+	Requests int
+	// Window is the time frame the Requests are allowed in.
+	Window time.Duration
+	// Burst is the number of requests a client can send at once, defaults to Requests.
+	Burst int
+	// Key tells the clients apart, defaults to [KeyByClientIP].
+	Key RateLimitKey
+}
+
+// RateLimitKey returns the key identifying the client sending a request.
+type RateLimitKey func(r *http.Request) string
+
+// KeyByClientIP identifies clients by the IP address the request is sent from.
+// Headers such as 'X-Forwarded-For' are not taken into account, use a middleware
+// setting the RemoteAddr of the request if the server is run behind a proxy.
+func KeyByClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// KeyByPrincipal identifies clients by the principal stored via [WithPrincipal]
+// and falls back to [KeyByClientIP] for requests without principal.
+func KeyByPrincipal(r *http.Request) string {
+	if principal, ok := Principal(r); ok {
+		return "principal:" + principal
+	}
+	return "ip:" + KeyByClientIP(r)
+}
+
+func (l RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate returns the number of tokens added to a bucket per second.
+func (l RateLimit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// Policy returns the limit in the format of the 'RateLimit-Policy' header.
+func (l RateLimit) Policy() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", l.Requests, int(math.Ceil(l.Window.Seconds())), l.burst())
+}
+
+// bucket holds the tokens of a client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter holds the buckets of all clients of an endpoint.
+type limiter struct {
+	limit   RateLimit
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func newLimiter(l RateLimit, now func() time.Time) *limiter {
+	if l.Key == nil {
+		l.Key = KeyByClientIP
+	}
+	return &limiter{limit: l, now: now, buckets: map[string]*bucket{}, swept: now()}
+}
+
+// take removes a token from the bucket of the key. It returns whether a token
+// was available, the tokens remaining and the time until the next token is
+// available as well as the time until the bucket is full.
+func (l *limiter) take(key string) (bool, int, time.Duration, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t := l.now()
+	capacity, rate := float64(l.limit.burst()), l.limit.rate()
+	full := time.Duration(capacity / rate * float64(time.Second))
+	// buckets which have been refilled completely are dropped
+	if t.Sub(l.swept) > full {
+		for k, b := range l.buckets {
+			if t.Sub(b.last) > full {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = t
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: t}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+t.Sub(b.last).Seconds()*rate)
+	b.last = t
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	retry := time.Duration(0)
+	if b.tokens < 1 {
+		retry = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	reset := time.Duration((capacity - b.tokens) / rate * float64(time.Second))
+	return allowed, int(b.tokens), retry, reset
+}
+
+// rateLimit enforces the RateLimit of the endpoint if set.
+func (e *Endpoint) rateLimit(next http.HandlerFunc) http.HandlerFunc {
+	if e.RateLimit == nil || e.RateLimit.Requests <= 0 || e.RateLimit.Window <= 0 {
+		return next
+	}
+	state := e.sharedState()
+	state.limiterOnce.Do(func() { state.limiter = newLimiter(*e.RateLimit, state.time) })
+	l := state.limiter
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed, remaining, retry, reset := l.take(l.limit.Key(r))
+		w.Header().Set("RateLimit-Limit", fmt.Sprint(l.limit.burst()))
+		w.Header().Set("RateLimit-Remaining", fmt.Sprint(remaining))
+		w.Header().Set("RateLimit-Reset", fmt.Sprint(seconds(reset)))
+		w.Header().Set("RateLimit-Policy", l.limit.Policy())
+		if !allowed {
+			w.Header().Set("Retry-After", fmt.Sprint(seconds(retry)))
+			e.respondError(http.StatusTooManyRequests, "rate limit exceeded", nil, w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// seconds rounds the duration up to full seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
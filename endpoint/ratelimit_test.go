@@ -0,0 +1,131 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	type request struct {
+		after      time.Duration
+		remoteAddr string
+		principal  string
+		status     int
+		headers    map[string]string
+	}
+	cases := map[string]struct {
+		limit    RateLimit
+		requests []request
+	}{
+		"Requests within the limit": {
+			limit: RateLimit{Requests: 2, Window: time.Minute},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK, headers: map[string]string{
+					"RateLimit-Limit":     "2",
+					"RateLimit-Remaining": "1",
+					"RateLimit-Reset":     "30",
+					"RateLimit-Policy":    "2;w=60;burst=2",
+				}},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK, headers: map[string]string{"RateLimit-Remaining": "0"}},
+			},
+		},
+		"Limit exceeded": {
+			limit: RateLimit{Requests: 2, Window: time.Minute},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+				{remoteAddr: "10.0.0.1:4321", status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "30"}},
+				{remoteAddr: "10.0.0.2:1234", status: http.StatusOK},
+			},
+		},
+		"Bucket refilled": {
+			limit: RateLimit{Requests: 1, Window: time.Minute},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+				{after: 30 * time.Second, remoteAddr: "10.0.0.1:1234", status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "30"}},
+				{after: time.Minute, remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+			},
+		},
+		"Burst above the rate": {
+			limit: RateLimit{Requests: 1, Window: time.Second, Burst: 3},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK, headers: map[string]string{"RateLimit-Policy": "1;w=1;burst=3"}},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusTooManyRequests},
+			},
+		},
+		"Clients told apart by principal": {
+			limit: RateLimit{Requests: 1, Window: time.Minute, Key: KeyByPrincipal},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1234", principal: "alice", status: http.StatusOK},
+				{remoteAddr: "10.0.0.1:1234", principal: "bob", status: http.StatusOK},
+				{remoteAddr: "10.0.0.2:1234", principal: "alice", status: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.1:1234", status: http.StatusOK},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clock := start
+			limit := tc.limit
+			e := &Endpoint{
+				RateLimit:     &limit,
+				ErrorResponse: testErrorResponse{},
+				Handler:       func(w http.ResponseWriter, r *http.Request) {},
+				state:         &endpointState{now: func() time.Time { return clock }},
+			}
+			handler := e.handlerFunc()
+			for i, req := range tc.requests {
+				clock = start.Add(req.after)
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.RemoteAddr = req.remoteAddr
+				if req.principal != "" {
+					r = WithPrincipal(r, req.principal)
+				}
+				w := httptest.NewRecorder()
+				handler(w, r)
+				if w.Code != req.status {
+					t.Errorf("status of request %d is not as expected, have %d, need %d", i, w.Code, req.status)
+				}
+				for key, value := range req.headers {
+					if have := w.Header().Get(key); have != value {
+						t.Errorf("header %s of request %d is not as expected, have %q, need %q", key, i, have, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitSharedByRoutes(t *testing.T) {
+	e := &Endpoint{
+		RateLimit:     &RateLimit{Requests: 2, Window: time.Minute},
+		ErrorResponse: testErrorResponse{},
+		Handler:       func(w http.ResponseWriter, r *http.Request) {},
+		state:         &endpointState{now: func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }},
+	}
+	endpoints := Endpoints{}
+	if err := endpoints.AddVersioned("/{version}/todos/", http.MethodGet, e, "v1", "v2"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	handler := routerWithEndpoints(t, endpoints)
+	for i, req := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/v1/todos/", http.StatusOK},
+		{http.MethodHead, "/v1/todos/", http.StatusOK},
+		{http.MethodGet, "/v2/todos/", http.StatusTooManyRequests},
+	} {
+		w := serve(handler, httptest.NewRequest(req.method, req.path, nil))
+		if w.Code != req.status {
+			t.Errorf("status of request %d is not as expected, have %d, need %d", i, w.Code, req.status)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/mux"
+)
+
+// Router is implemented by the routers an [Endpoints] collection can be attached
+// to via [Endpoints.Populate]. Adapters are provided for [github.com/gorilla/mux],
+// [net/http.ServeMux] and [github.com/go-chi/chi/v5].
+type Router interface {
+	// Handle registers the handler for the route provided.
+	Handle(route Route, handler http.Handler)
+	// PathValue returns the value of the path parameter name extracted by the
+	// router from the request. The boolean is false if the parameter is not set.
+	PathValue(r *http.Request, name string) (string, bool)
+}
+
+// FallbackRouter is implemented by routers which allow to handle the requests
+// none of the routes registered matches, either by path or by method. The
+// adapters provided all implement it.
+type FallbackRouter interface {
+	Router
+	// HandleFallback registers the handler for requests no route matches.
+	HandleFallback(handler http.Handler)
+}
+
+// Route describes what requests an [Endpoint] is registered for.
+type Route struct {
+	// Method is the HTTP method of the route.
+	Method string
+	// Path is the path of the route, path parameters are written as '{name}'.
+	// The path always starts with a slash, the paths of Prefix routes always end
+	// with a slash.
+	Path string
+	// Prefix is true if the route matches all paths starting with Path. Such
+	// routes are added by using a path ending with '*/' or a catch-all parameter.
+	Prefix bool
+	// CatchAll is the name of the parameter capturing the remainder of the path
+	// of a Prefix route, empty if the remainder is not captured.
+	CatchAll string
+	// Patterns hold the regular expressions path parameters must match by name.
+	Patterns map[string]string
+	// Automatic is true for the routes of the methods answered automatically,
+	// see [Endpoints.Automatic].
+	Automatic bool
+}
+
+// pattern returns the path of the route with the patterns of the path
+// parameters embedded as '{name:pattern}'.
+func (r Route) pattern() string {
+	path := r.Path
+	for name, pattern := range r.Patterns {
+		path = strings.ReplaceAll(path, "{"+name+"}", "{"+name+":"+pattern+"}")
+	}
+	return path
+}
+
+type routerKey struct{}
+
+// Populate attaches all endpoints of the collection to the [Router] provided,
+// applying the settings of the [Config] provided. Unless DisableAutoMethods is
+// set, the endpoints returned by [Endpoints.Automatic] are attached as well.
+//
+// If the router is a [FallbackRouter], the requests no route matches are
+// answered by the collection: requests to a path of the collection using a
+// method the path does not allow are answered with '405 Method Not Allowed'
+// rendered through the ErrorResponse of the endpoints of the most specific path
+// matching, all others with '404 Not Found'. Requests to a path of the
+// collection with path parameters not matching their constraints are answered
+// with '404 Not Found' rendered through the ErrorResponse of the endpoints of
+// the path.
+//
+// Requests using the non-canonical form of a path regarding its trailing slash
+// and one of the methods allowed for the path are redirected according to
+// TrailingSlashRedirect or answered with '404 Not Found' rendered through the
+// ErrorResponse of the endpoints of the path, see [TrailingSlash].
+//
+// The collection is validated using [Endpoints.Validate] first, if conflicts
+// are found they are returned as error and nothing is attached. Routes are
+// attached most specific first, so routers with first-match semantics route
+// requests deterministically.
+func (c *Endpoints) Populate(router Router, cfg Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	templates := c.templates()
+	regs := []registration{}
+	// versions of an endpoint sharing a path are served by a single route
+	// dispatching by the version requested
+	add := func(set Endpoints, automatic bool) {
+		type route struct{ path, method string }
+		handlers := map[route][]versioned{}
+		for _, caller := range set.callers() {
+			e := set[caller]
+			r := route{path: caller.Path, method: caller.Method}
+			handlers[r] = append(handlers[r], versioned{version: caller.Version, handler: withEndpoint(e, caller, cfg.chain(e))})
+		}
+		for r, h := range handlers {
+			tpl := templates[r.path]
+			handler := h[0].handler
+			if len(h) > 1 || (h[0].version != "" && !tpl.versionByPath) {
+				handler = c.dispatchVersion(r.path, h, cfg)
+			}
+			route := tpl.route(r.method)
+			route.Automatic = automatic
+			regs = append(regs, registration{tpl: tpl, route: route, handler: withRouter(router, handler)})
+		}
+	}
+	add(*c, false)
+	add(c.Automatic(cfg), true)
+	// the non-canonical form of the paths is registered explicitly, as some
+	// routers such as http.ServeMux redirect on their own otherwise
+	methods := c.methods()
+	for path, alternate := range c.alternatePaths() {
+		tpl := templates[path]
+		handler := withRouter(router, c.alternateHandler(path, cfg))
+		for _, method := range c.Allowed(path, cfg) {
+			route := tpl.route(method)
+			route.Path = alternate
+			route.Automatic = !contains(methods[path], method)
+			regs = append(regs, registration{tpl: tpl, route: route, handler: handler})
+		}
+	}
+	sortRegistrations(regs)
+	for _, reg := range regs {
+		router.Handle(reg.route, reg.handler)
+	}
+	if r, ok := router.(FallbackRouter); ok {
+		r.HandleFallback(withRouter(router, c.fallback(cfg)))
+	}
+	return nil
+}
+
+// withRouter stores the router in the request context to allow path parameters
+// to be read regardless of the router used.
+func withRouter(router Router, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), routerKey{}, router)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// pathValue returns the path parameter name using the router the request was
+// dispatched by. Requests not dispatched via [Endpoints.Populate] fall back to
+// [github.com/gorilla/mux.Vars].
+func pathValue(r *http.Request, name string) (string, bool) {
+	if router, ok := r.Context().Value(routerKey{}).(Router); ok {
+		return router.PathValue(r, name)
+	}
+	v, ok := mux.Vars(r)[name]
+	return v, ok
+}
+
+// MuxRouter returns a [Router] which registers endpoints with a [github.com/gorilla/mux.Router].
+func MuxRouter(router *mux.Router) Router {
+	return muxRouter{router}
+}
+
+type muxRouter struct {
+	router *mux.Router
+}
+
+func (m muxRouter) Handle(route Route, handler http.Handler) {
+	path := route.pattern()
+	if route.Prefix {
+		if route.CatchAll != "" {
+			path += "{" + route.CatchAll + ":.*}"
+		}
+		m.router.PathPrefix(path).Handler(handler).Methods(route.Method)
+		return
+	}
+	m.router.Path(path).Handler(handler).Methods(route.Method)
+}
+
+func (m muxRouter) HandleFallback(handler http.Handler) {
+	m.router.NotFoundHandler = handler
+	m.router.MethodNotAllowedHandler = handler
+}
+
+func (m muxRouter) PathValue(r *http.Request, name string) (string, bool) {
+	v, ok := mux.Vars(r)[name]
+	return v, ok
+}
+
+// ServeMuxRouter returns a [Router] which registers endpoints with a [net/http.ServeMux]
+// using method and wildcard patterns. Path parameters must span a whole path
+// segment as required by [net/http.ServeMux]. As [net/http.ServeMux] does not
+// support constraints, routes only differing by the constraints of their path
+// parameters share a pattern and requests are dispatched to the most specific
+// route the constraints of which match. Requests matching none of them are
+// answered by the fallback handler. The fallback handler is registered for the
+// pattern '/', which therefore must not be used otherwise.
+func ServeMuxRouter(router *http.ServeMux) Router {
+	return &serveMuxRouter{router: router, routes: map[string][]serveMuxRoute{}}
+}
+
+type serveMuxRouter struct {
+	router *http.ServeMux
+	// routes holds the routes by method and pattern without parameter names,
+	// in the order registered
+	routes   map[string][]serveMuxRoute
+	fallback http.Handler
+}
+
+// serveMuxRoute is one of the routes sharing a pattern of a [net/http.ServeMux].
+type serveMuxRoute struct {
+	handler     http.Handler
+	names       []string
+	constraints map[string]*regexp.Regexp
+}
+
+// serveMuxAliasKey is the context key of the names of the path parameters in
+// the pattern registered by the names used by the route dispatched to.
+type serveMuxAliasKey struct{}
+
+func (s *serveMuxRouter) Handle(route Route, handler http.Handler) {
+	path := route.Path
+	if !route.Prefix && strings.HasSuffix(path, "/") {
+		// a pattern ending with a slash matches all paths below, '{$}' only
+		// matches the path itself
+		path += "{$}"
+	} else if route.CatchAll != "" {
+		path += "{" + route.CatchAll + "...}"
+	}
+	rt := serveMuxRoute{handler: handler, constraints: map[string]*regexp.Regexp{}}
+	for _, param := range tidyParam.FindAllStringSubmatch(path, -1) {
+		if param[1] != "$" {
+			rt.names = append(rt.names, strings.TrimSuffix(param[1], "..."))
+		}
+	}
+	for name, pattern := range route.Patterns {
+		rt.constraints[name] = regexp.MustCompile("^(?:" + pattern + ")$")
+	}
+	shape := tidyParam.ReplaceAllString(path, "{}")
+	key := route.Method + " " + shape
+	_, registered := s.routes[key]
+	s.routes[key] = append(s.routes[key], rt)
+	if registered || (route.Automatic && route.Method == http.MethodHead) {
+		// GET patterns match HEAD requests as well, an explicit HEAD pattern
+		// conflicts with the GET patterns of more specific sibling paths
+		return
+	}
+	s.router.HandleFunc(route.Method+" "+path, func(w http.ResponseWriter, r *http.Request) {
+		routes := s.routes[key]
+		if heads, ok := s.routes[http.MethodHead+" "+shape]; ok && r.Method == http.MethodHead {
+			routes = heads
+		}
+		s.dispatch(routes, rt.names, w, r)
+	})
+}
+
+// dispatch serves the request using the first of the routes sharing a pattern
+// the constraints of which match. The path parameters are read using the names
+// of the pattern registered.
+func (s *serveMuxRouter) dispatch(routes []serveMuxRoute, names []string, w http.ResponseWriter, r *http.Request) {
+	for _, rt := range routes {
+		var aliases map[string]string
+		for i, name := range rt.names {
+			if name != names[i] {
+				if aliases == nil {
+					aliases = map[string]string{}
+				}
+				aliases[name] = names[i]
+			}
+		}
+		matches := true
+		for name, rex := range rt.constraints {
+			if alias, ok := aliases[name]; ok {
+				name = alias
+			}
+			if !rex.MatchString(r.PathValue(name)) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if aliases != nil {
+			r = r.WithContext(context.WithValue(r.Context(), serveMuxAliasKey{}, aliases))
+		}
+		rt.handler.ServeHTTP(w, r)
+		return
+	}
+	if s.fallback != nil {
+		s.fallback.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *serveMuxRouter) HandleFallback(handler http.Handler) {
+	s.fallback = handler
+	s.router.Handle("/", handler)
+}
+
+func (s *serveMuxRouter) PathValue(r *http.Request, name string) (string, bool) {
+	if aliases, ok := r.Context().Value(serveMuxAliasKey{}).(map[string]string); ok {
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+	}
+	v := r.PathValue(name)
+	return v, v != ""
+}
+
+// ChiRouter returns a [Router] which registers endpoints with a [github.com/go-chi/chi/v5.Router].
+func ChiRouter(router chi.Router) Router {
+	return chiRouter{router}
+}
+
+type chiRouter struct {
+	router chi.Router
+}
+
+func (c chiRouter) Handle(route Route, handler http.Handler) {
+	path := route.pattern()
+	if route.Prefix {
+		path += "*"
+	}
+	if route.CatchAll != "" {
+		// chi captures the remainder of the path as '*'
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				rctx.URLParams.Add(route.CatchAll, rctx.URLParam("*"))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	c.router.Method(route.Method, path, handler)
+}
+
+func (c chiRouter) HandleFallback(handler http.Handler) {
+	c.router.NotFound(handler.ServeHTTP)
+	c.router.MethodNotAllowed(handler.ServeHTTP)
+}
+
+func (c chiRouter) PathValue(r *http.Request, name string) (string, bool) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "", false
+	}
+	for i := len(rctx.URLParams.Keys) - 1; i >= 0; i-- {
+		if rctx.URLParams.Keys[i] == name {
+			return rctx.URLParams.Values[i], true
+		}
+	}
+	return "", false
+}
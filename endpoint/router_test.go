@@ -0,0 +1,274 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPopulate(t *testing.T) {
+	cases := map[string]struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		"Path parameter": {
+			method: http.MethodGet,
+			path:   "/todos/shopping/",
+			status: http.StatusOK,
+			body:   "show shopping",
+		},
+		"Other method": {
+			method: http.MethodPost,
+			path:   "/todos/",
+			status: http.StatusOK,
+			body:   "add",
+		},
+		"Prefix": {
+			method: http.MethodGet,
+			path:   "/static/css/main.css",
+			status: http.StatusOK,
+			body:   "static",
+		},
+		"Constraint": {
+			method: http.MethodGet,
+			path:   "/orgs/acme/repos/42/",
+			status: http.StatusOK,
+			body:   "repo acme 42",
+		},
+		"Constraint mismatch": {
+			method: http.MethodGet,
+			path:   "/orgs/acme/repos/x-1/",
+			status: http.StatusNotFound,
+		},
+		"Constraint of sibling": {
+			method: http.MethodGet,
+			path:   "/orgs/acme/repos/latest/",
+			status: http.StatusOK,
+			body:   "alias acme latest",
+		},
+		"Catch all": {
+			method: http.MethodGet,
+			path:   "/files/docs/a/b.txt",
+			status: http.StatusOK,
+			body:   "file docs a/b.txt",
+		},
+		"Not below path": {
+			method: http.MethodGet,
+			path:   "/todos/shopping/list/",
+			status: http.StatusNotFound,
+		},
+	}
+
+	endpoints := Endpoints{}
+	show := &Endpoint{Name: "show"}
+	show.Handler = func(w http.ResponseWriter, r *http.Request) {
+		name, _ := show.GetParamAsString("name", r)
+		fmt.Fprintf(w, "show %s", name)
+	}
+	endpoints.Add("/todos/{name}/", http.MethodGet, show)
+	repo := &Endpoint{}
+	repo.Handler = func(w http.ResponseWriter, r *http.Request) {
+		org, _ := repo.GetParamAsString("org", r)
+		id, _ := repo.GetParamAsInt("id", r)
+		fmt.Fprintf(w, "repo %s %d", org, id)
+	}
+	endpoints.Add("/orgs/{org | Org}/repos/{id:int | Repo id}/", http.MethodGet, repo)
+	alias := &Endpoint{}
+	alias.Handler = func(w http.ResponseWriter, r *http.Request) {
+		owner, _ := alias.GetParamAsString("owner", r)
+		name, _ := alias.GetParamAsString("alias", r)
+		fmt.Fprintf(w, "alias %s %s", owner, name)
+	}
+	endpoints.Add("/orgs/{owner}/repos/{alias:[a-z]+}/", http.MethodGet, alias)
+	file := &Endpoint{}
+	file.Handler = func(w http.ResponseWriter, r *http.Request) {
+		bucket, _ := file.GetParamAsString("bucket", r)
+		path, _ := file.GetParamAsString("path", r)
+		fmt.Fprintf(w, "file %s %s", bucket, path)
+	}
+	endpoints.Add("/files/{bucket}/{path...}", http.MethodGet, file)
+	endpoints.Add("/todos/", http.MethodPost, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "add")
+	}})
+	endpoints.Add("/static/*/", http.MethodGet, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "static")
+	}})
+
+	for routerName, newRouter := range routers() {
+		handler, router := newRouter()
+		endpoints.Populate(router, Config{})
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				w := serve(handler, httptest.NewRequest(tc.method, tc.path, nil))
+				if w.Code != tc.status {
+					t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+				}
+				if tc.body != "" && w.Body.String() != tc.body {
+					t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+				}
+			})
+		}
+	}
+}
+
+func TestPopulateSiblings(t *testing.T) {
+	cases := map[string]struct {
+		method string
+		path   string
+		status int
+		allow  string
+		body   string
+	}{
+		"Static sibling": {
+			method: http.MethodPost,
+			path:   "/todos/special/",
+			status: http.StatusOK,
+			body:   "special",
+		},
+		"Parameter sibling": {
+			method: http.MethodGet,
+			path:   "/todos/special/",
+			status: http.StatusOK,
+			body:   "show special",
+		},
+		"Static sibling with get": {
+			method: http.MethodGet,
+			path:   "/todos/archive/",
+			status: http.StatusOK,
+			body:   "archive",
+		},
+		"Constrained sibling": {
+			method: http.MethodGet,
+			path:   "/todos/42/",
+			status: http.StatusOK,
+			body:   "item 42",
+		},
+		"Parameter sibling with head": {
+			method: http.MethodHead,
+			path:   "/todos/special/",
+			status: http.StatusOK,
+		},
+		"Method not allowed by any sibling": {
+			method: http.MethodPut,
+			path:   "/todos/special/",
+			status: http.StatusMethodNotAllowed,
+			allow:  "GET, HEAD, OPTIONS, POST",
+			body:   "error: method not allowed",
+		},
+	}
+
+	ok := func(body string) *Endpoint {
+		return &Endpoint{ErrorResponse: testErrorResponse{}, Handler: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}}
+	}
+	endpoints := Endpoints{}
+	endpoints.Add("/todos/special/", http.MethodPost, ok("special"))
+	endpoints.Add("/todos/archive/", http.MethodGet, ok("archive"))
+	show := &Endpoint{ErrorResponse: testErrorResponse{}}
+	show.Handler = func(w http.ResponseWriter, r *http.Request) {
+		name, _ := show.GetParamAsString("name", r)
+		fmt.Fprintf(w, "show %s", name)
+	}
+	endpoints.Add("/todos/{name}/", http.MethodGet, show)
+	item := &Endpoint{ErrorResponse: testErrorResponse{}}
+	item.Handler = func(w http.ResponseWriter, r *http.Request) {
+		id, _ := item.GetParamAsInt("id", r)
+		fmt.Fprintf(w, "item %d", id)
+	}
+	endpoints.Add("/todos/{id:int}/", http.MethodGet, item)
+
+	for routerName, newRouter := range routers() {
+		handler, router := newRouter()
+		endpoints.Populate(router, Config{})
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				w := serve(handler, httptest.NewRequest(tc.method, tc.path, nil))
+				if w.Code != tc.status {
+					t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+				}
+				if allow := w.Header().Get("Allow"); allow != tc.allow {
+					t.Errorf("allow header is not as expected, have %q, need %q", allow, tc.allow)
+				}
+				if tc.body != "" && w.Body.String() != tc.body {
+					t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+				}
+			})
+		}
+	}
+}
+
+func TestPopulateFallback(t *testing.T) {
+	cases := map[string]struct {
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		"Method not allowed": {
+			method:      http.MethodDelete,
+			path:        "/todos/shopping/",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/problem+json",
+			body:        `"detail": "method not allowed"`,
+		},
+		"Method not allowed below prefix": {
+			method:      http.MethodPost,
+			path:        "/static/css/main.css",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/problem+json",
+			body:        `"detail": "method not allowed"`,
+		},
+		"Constraint mismatch": {
+			method:      http.MethodGet,
+			path:        "/repos/x/",
+			status:      http.StatusNotFound,
+			contentType: "application/problem+json",
+			body:        `"detail": "not found"`,
+		},
+		"Method not allowed with constraint": {
+			method:      http.MethodPost,
+			path:        "/repos/42/",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "application/problem+json",
+			body:        `"detail": "method not allowed"`,
+		},
+		"Unknown path": {
+			method:      http.MethodGet,
+			path:        "/unknown/",
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        "404 page not found",
+		},
+	}
+
+	endpoints := Endpoints{}
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	endpoints.Add("/todos/{name}/", http.MethodGet, &Endpoint{ErrorResponse: ProblemDetails{}, Handler: handler})
+	endpoints.Add("/static/*/", http.MethodGet, &Endpoint{ErrorResponse: ProblemDetails{}, Handler: handler})
+	endpoints.Add("/repos/{id:int}/", http.MethodGet, &Endpoint{ErrorResponse: ProblemDetails{}, Handler: handler})
+
+	for routerName, newRouter := range routers() {
+		handler, router := newRouter()
+		endpoints.Populate(router, Config{})
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				w := serve(handler, httptest.NewRequest(tc.method, tc.path, nil))
+				if w.Code != tc.status {
+					t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+				}
+				if ct := w.Header().Get("Content-Type"); ct != tc.contentType {
+					t.Errorf("content type is not as expected, have %q, need %q", ct, tc.contentType)
+				}
+				if !strings.Contains(w.Body.String(), tc.body) {
+					t.Errorf("body is not as expected, have %q, need it to contain %q", w.Body.String(), tc.body)
+				}
+			})
+		}
+	}
+}
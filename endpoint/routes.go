@@ -0,0 +1,128 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+// RouteInfo describes a single route of an [Endpoints] collection as returned
+// by [Endpoints.Routes].
+type RouteInfo struct {
+	Method  string   `json:"method" yaml:"method"`
+	Path    string   `json:"path" yaml:"path"`
+	Version string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Name    string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Auth is the name of the [Auth] of the endpoint.
+	Auth string `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Hidden is true if the endpoint is not represented in the OpenAPI document.
+	Hidden bool `json:"hidden" yaml:"hidden"`
+	// Automatic is true if the route is answered automatically, see [Endpoints.Automatic].
+	Automatic bool `json:"automatic" yaml:"automatic"`
+	// Errors are the errors registered via [Endpoint.RegisterErrorValue].
+	Errors []RouteError `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// RouteError describes an error registered on an [Endpoint].
+type RouteError struct {
+	Status  int    `json:"status" yaml:"status"`
+	Details string `json:"details" yaml:"details"`
+}
+
+// RouteTable lists the routes of an [Endpoints] collection.
+type RouteTable []RouteInfo
+
+// String renders the routes as a table.
+func (t RouteTable) String() string {
+	b := &strings.Builder{}
+	w := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tNAME\tTAGS\tAUTH\tFLAGS\tERRORS\tVERSION")
+	for _, r := range t {
+		flags := []string{}
+		if r.Hidden {
+			flags = append(flags, "hidden")
+		}
+		if r.Automatic {
+			flags = append(flags, "automatic")
+		}
+		errs := make([]string, len(r.Errors))
+		for i, err := range r.Errors {
+			errs[i] = fmt.Sprint(err.Status)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, r.Name,
+			strings.Join(r.Tags, ","), r.Auth, strings.Join(flags, ","), strings.Join(errs, ","), r.Version)
+	}
+	w.Flush()
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Routes returns all routes of the collection sorted by path, method and version,
+// including hidden endpoints as well as the endpoints answered automatically
+// according to the [Config] provided.
+func (c Endpoints) Routes(cfg Config) RouteTable {
+	out := RouteTable{}
+	for i, set := range []Endpoints{c, c.Automatic(cfg)} {
+		for _, caller := range set.callers() {
+			e := set[caller]
+			info := RouteInfo{
+				Method:    caller.Method,
+				Path:      caller.Path,
+				Version:   caller.Version,
+				Name:      e.Name,
+				Tags:      e.Tags,
+				Hidden:    e.Hidden,
+				Automatic: i == 1,
+			}
+			if e.Auth != nil {
+				info.Auth = e.Auth.Name
+			}
+			for _, err := range e.registeredErrors {
+				info.Errors = append(info.Errors, RouteError{Status: err.Status, Details: err.Details})
+			}
+			sort.Slice(info.Errors, func(i, j int) bool {
+				return info.Errors[i].Status < info.Errors[j].Status
+			})
+			out = append(out, info)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
+
+// RoutesHandler returns a [http.Handler] which renders the routes of the
+// collection as JSON, YAML or text depending on the 'accept' header of the
+// request, see [Endpoints.Routes]. The routes are read on every request, so
+// endpoints added later are listed as well. If auth is provided, the handler
+// is wrapped in its MiddlewareInjector.
+func (c *Endpoints) RoutesHandler(auth *Auth, cfg Config) http.Handler {
+	e := Endpoint{
+		Name:        "routes",
+		Description: "List the routes of the collection",
+		Auth:        auth,
+		Hidden:      true,
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		respond.Auto(w, r, http.StatusOK, c.Routes(cfg))
+	}
+	if auth != nil && auth.MiddlewareInjector != nil {
+		handler = auth.MiddlewareInjector(e, handler)
+	}
+	return http.HandlerFunc(handler)
+}
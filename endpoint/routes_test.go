@@ -0,0 +1,107 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	auth := &Auth{Name: "BasicAuth"}
+	endpoints := Endpoints{}
+	show := &Endpoint{Name: "show", Tags: []string{"todos"}, Auth: auth}
+	show.RegisterErrorValue(http.StatusNotFound, "not found")
+	show.RegisterErrorValue(http.StatusBadRequest, "bad request")
+	endpoints.Add("/todos/{name}/", http.MethodGet, show)
+	endpoints.Add("/internal/", http.MethodPost, &Endpoint{Name: "internal", Hidden: true})
+
+	expected := RouteTable{
+		{Method: http.MethodOptions, Path: "/internal/", Hidden: true, Name: "options", Automatic: true},
+		{Method: http.MethodPost, Path: "/internal/", Name: "internal", Hidden: true},
+		{Method: http.MethodGet, Path: "/todos/{name}/", Name: "show", Tags: []string{"todos"}, Auth: "BasicAuth",
+			Errors: []RouteError{{Status: 400, Details: "bad request"}, {Status: 404, Details: "not found"}}},
+		{Method: http.MethodHead, Path: "/todos/{name}/", Name: "show", Tags: []string{"todos"}, Auth: "BasicAuth", Automatic: true,
+			Errors: []RouteError{{Status: 400, Details: "bad request"}, {Status: 404, Details: "not found"}}},
+		{Method: http.MethodOptions, Path: "/todos/{name}/", Name: "options", Tags: []string{"todos"}, Automatic: true},
+	}
+	if routes := endpoints.Routes(Config{}); !reflect.DeepEqual(routes, expected) {
+		t.Errorf("routes are not as expected, have %+v, need %+v", routes, expected)
+	}
+	if routes := endpoints.Routes(Config{DisableAutoMethods: true}); len(routes) != 2 {
+		t.Errorf("routes are expected to omit automatic methods if disabled, have %+v", routes)
+	}
+}
+
+func TestRoutesHandler(t *testing.T) {
+	auth := &Auth{
+		Name: "token",
+		MiddlewareInjector: func(e Endpoint, next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				next(w, r)
+			}
+		},
+	}
+	endpoints := &Endpoints{}
+	handler := endpoints.RoutesHandler(auth, Config{})
+	endpoints.Add("/todos/", http.MethodGet, &Endpoint{Name: "list"})
+
+	cases := map[string]struct {
+		authorization string
+		accept        string
+		status        int
+		contains      string
+	}{
+		"Without authorization": {
+			status: http.StatusUnauthorized,
+		},
+		"Rendered as JSON": {
+			authorization: "secret",
+			status:        http.StatusOK,
+			contains:      `"path": "/todos/"`,
+		},
+		"Rendered as YAML": {
+			authorization: "secret",
+			accept:        "text/yaml",
+			status:        http.StatusOK,
+			contains:      "path: /todos/",
+		},
+		"Rendered as text": {
+			authorization: "secret",
+			accept:        "text/plain",
+			status:        http.StatusOK,
+			contains:      "GET      /todos/  list\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/_routes", nil)
+			r.Header.Set("Authorization", tc.authorization)
+			r.Header.Set("Accept", tc.accept)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			if !strings.Contains(w.Body.String(), tc.contains) {
+				t.Errorf("body is expected to contain %q, have %q", tc.contains, w.Body.String())
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/_routes", nil)
+	r.Header.Set("Authorization", "secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	routes := RouteTable{}
+	if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil || len(routes) != 3 {
+		t.Errorf("routes are expected to be rendered as json, have %q", w.Body.String())
+	}
+}
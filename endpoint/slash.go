@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrailingSlash defines how trailing slashes of the paths added to [Endpoints]
+// are handled. Paths are canonicalized using [TrailingSlashAppend] unless they
+// are added to a [Group] created with [WithTrailingSlash]. Paths of prefix routes
+// ending with '*/' or a catch-all parameter are not affected.
+type TrailingSlash int
+
+const (
+	TrailingSlashAppend TrailingSlash = iota // paths are canonicalized to end with a slash
+	TrailingSlashStrip                       // paths are canonicalized to end without a slash
+	TrailingSlashStrict                      // paths are used as added, without canonicalization
+)
+
+// WithTrailingSlash sets the [TrailingSlash] policy the paths of the endpoints of
+// the group are canonicalized with. Mounted endpoints keep the policy they were
+// added with unless the option is provided to [Endpoints.Mount].
+func WithTrailingSlash(policy TrailingSlash) GroupOption {
+	return func(g *Group) {
+		g.trailingSlash = &policy
+	}
+}
+
+// alternatePaths returns the non-canonical form regarding the trailing slash of
+// the paths of the collection by path. Paths only added using [TrailingSlashStrict],
+// prefix paths, the root path and paths the non-canonical form of which matches
+// a path of the collection itself have none.
+func (c Endpoints) alternatePaths() map[string]string {
+	shapes := map[string]bool{}
+	for _, tpl := range c.templates() {
+		shapes[tpl.shape()] = true
+	}
+	out := map[string]string{}
+	for caller := range c {
+		tpl := c.template(caller)
+		if tpl.trailingSlash == TrailingSlashStrict || tpl.isPrefix || tpl.path == "/" {
+			continue
+		}
+		alternate := tpl.path + "/"
+		if strings.HasSuffix(tpl.path, "/") {
+			alternate = strings.TrimSuffix(tpl.path, "/")
+		}
+		alt := tpl
+		alt.path = alternate
+		if !shapes[alt.shape()] {
+			out[tpl.path] = alternate
+		}
+	}
+	return out
+}
+
+// alternateHandler returns the handler answering requests using the non-canonical
+// form of the path provided. Requests are redirected to the canonical form using
+// TrailingSlashRedirect of the [Config] provided or answered with '404 Not Found'
+// rendered through the ErrorResponse of the endpoints of the path.
+func (c Endpoints) alternateHandler(path string, cfg Config) http.HandlerFunc {
+	if cfg.TrailingSlashRedirect != 0 {
+		return func(w http.ResponseWriter, r *http.Request) {
+			target := *r.URL
+			if strings.HasSuffix(target.Path, "/") {
+				target.Path = strings.TrimSuffix(target.Path, "/")
+			} else {
+				target.Path += "/"
+			}
+			target.RawPath = ""
+			http.Redirect(w, r, target.RequestURI(), cfg.TrailingSlashRedirect)
+		}
+	}
+	rep := c.representative(path)
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		rep.respondError(http.StatusNotFound, "not found", nil, w, r)
+	}
+	return wrap(*rep, notFound, cfg.Middlewares)
+}
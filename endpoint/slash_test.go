@@ -0,0 +1,151 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrailingSlash(t *testing.T) {
+	cases := map[string]struct {
+		policy   TrailingSlash
+		redirect int
+		method   string
+		path     string
+		caller   string
+		status   int
+		location string
+		body     string
+	}{
+		"Append with canonical path": {
+			policy: TrailingSlashAppend,
+			path:   "/todos/x/?q=1",
+			caller: "/todos/{name}/",
+			status: http.StatusOK,
+		},
+		"Append with non-canonical path": {
+			policy: TrailingSlashAppend,
+			path:   "/todos/x",
+			caller: "/todos/{name}/",
+			status: http.StatusNotFound,
+			body:   "error: not found",
+		},
+		"Append with redirect": {
+			policy:   TrailingSlashAppend,
+			redirect: http.StatusPermanentRedirect,
+			path:     "/todos/x?q=1",
+			caller:   "/todos/{name}/",
+			status:   http.StatusPermanentRedirect,
+			location: "/todos/x/?q=1",
+		},
+		"Append with redirect and other method": {
+			policy:   TrailingSlashAppend,
+			redirect: http.StatusPermanentRedirect,
+			method:   http.MethodPost,
+			path:     "/todos/x",
+			caller:   "/todos/{name}/",
+			status:   http.StatusMethodNotAllowed,
+		},
+		"Append with redirect and head": {
+			policy:   TrailingSlashAppend,
+			redirect: http.StatusPermanentRedirect,
+			method:   http.MethodHead,
+			path:     "/todos/x",
+			caller:   "/todos/{name}/",
+			status:   http.StatusPermanentRedirect,
+			location: "/todos/x/",
+		},
+		"Strip with canonical path": {
+			policy: TrailingSlashStrip,
+			path:   "/todos/x",
+			caller: "/todos/{name}",
+			status: http.StatusOK,
+		},
+		"Strip with redirect": {
+			policy:   TrailingSlashStrip,
+			redirect: http.StatusMovedPermanently,
+			path:     "/todos/x/",
+			caller:   "/todos/{name}",
+			status:   http.StatusMovedPermanently,
+			location: "/todos/x",
+		},
+		"Strip with prefix route": {
+			policy:   TrailingSlashStrip,
+			redirect: http.StatusMovedPermanently,
+			path:     "/static/css/main.css",
+			caller:   "/todos/{name}",
+			status:   http.StatusOK,
+		},
+		"Strict with non-canonical path": {
+			policy:   TrailingSlashStrict,
+			redirect: http.StatusMovedPermanently,
+			path:     "/todos/x/",
+			caller:   "/todos/{name}",
+			status:   http.StatusNotFound,
+		},
+	}
+
+	for routerName, newRouter := range routers() {
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				endpoints := Endpoints{}
+				ok := func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "ok") }
+				g := endpoints.Group("/", WithTrailingSlash(tc.policy))
+				g.Add("/todos/{name}", http.MethodGet, &Endpoint{ErrorResponse: testErrorResponse{}, Handler: ok})
+				g.Add("/static/*", http.MethodGet, &Endpoint{Handler: ok})
+				if _, found := endpoints[Caller{Path: tc.caller, Method: http.MethodGet}]; !found {
+					t.Errorf("caller %s expected, have %v", tc.caller, endpoints)
+				}
+				if _, found := endpoints[Caller{Path: "/static/*/", Method: http.MethodGet}]; !found {
+					t.Errorf("prefix route is expected to be unaffected, have %v", endpoints)
+				}
+
+				handler, router := newRouter()
+				endpoints.Populate(router, Config{TrailingSlashRedirect: tc.redirect})
+				method := tc.method
+				if method == "" {
+					method = http.MethodGet
+				}
+				w := serve(handler, httptest.NewRequest(method, tc.path, nil))
+				if w.Code != tc.status {
+					t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+				}
+				if location := w.Header().Get("Location"); location != tc.location {
+					t.Errorf("location is not as expected, have %q, need %q", location, tc.location)
+				}
+				if tc.body != "" && w.Body.String() != tc.body {
+					t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+				}
+			})
+		}
+	}
+}
+
+func TestTrailingSlashMount(t *testing.T) {
+	cases := map[string]struct {
+		opts     []GroupOption
+		expected string
+	}{
+		"Policy of the endpoint kept": {
+			expected: "/api/todos",
+		},
+		"Policy of the mount applied": {
+			opts:     []GroupOption{WithTrailingSlash(TrailingSlashAppend)},
+			expected: "/api/todos/",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			other := Endpoints{}
+			other.Group("/", WithTrailingSlash(TrailingSlashStrip)).Add("/todos/", http.MethodGet, &Endpoint{})
+			endpoints := Endpoints{}
+			if err := endpoints.Mount("/api", other, tc.opts...); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if _, found := endpoints[Caller{Path: tc.expected, Method: http.MethodGet}]; !found {
+				t.Errorf("caller %s expected, have %v", tc.expected, endpoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+// inBody is the value of the 'in' tag which marks the field of the input of a
+// typed handler that receives the decoded request body.
+const inBody = "body"
+
+// TypedHandlerFunc is a handler which receives its input as well as returns its
+// output as Go values rather than dealing with the [http.Request] and the
+// [http.ResponseWriter] itself. Use [Typed] to turn it into an [Endpoint].
+type TypedHandlerFunc[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Typed wraps a [TypedHandlerFunc] into an [Endpoint]. The meta data of the
+// [Endpoint] is derived from the type parameters so that it cannot drift away
+// from what the handler actually does:
+//
+//   - Fields of In tagged as parameters (see [TagIn]) are added to Parameters
+//     and bound from the request using [Endpoint.Bind].
+//   - A field of In tagged with `in:"body"` receives the decoded request body,
+//     its type is used as RequestBody. If In is not a struct or does not contain
+//     any tagged fields, In itself is treated as the request body.
+//   - The zero value of Out is used as the 200 response, the value returned by
+//     the handler is rendered using [respond.Auto]. If Out is a slice and the
+//     endpoint is filterable via [Endpoint.Filterable], the list is filtered
+//     and sorted as requested. If the endpoint is paginated via
+//     [Endpoint.Paginate], the page requested is rendered using
+//     [Endpoint.RespondPage].
+//
+// The body is decoded and validated as described at [Endpoint.DecodeBody]. Invalid
+// parameters are answered with 400, errors returned by the handler are answered
+// the same way as errors returned by an [ErrHandlerFunc]. Typed panics if the tags of In cannot
+// be turned into [Parameter]s.
+func Typed[In, Out any](h TypedHandlerFunc[In, Out]) *Endpoint {
+	e := &Endpoint{Responses: map[int]interface{}{}}
+	var zeroOut Out
+	if t := reflect.TypeOf(zeroOut); t != nil {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		e.Responses[http.StatusOK] = reflect.Zero(t).Interface()
+	}
+
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	bodyIndex, bodyType, hasParams := inputLayout(inType)
+	if hasParams {
+		params, err := ParametersOf(reflect.Zero(inType).Interface())
+		if err != nil {
+			panic(fmt.Sprintf("cannot use %s as input of a typed handler: %s", inType, err.Error()))
+		}
+		e.Parameters = params
+	}
+	if bodyType != nil {
+		e.RequestBody = reflect.Zero(bodyType).Interface()
+		e.DecodeBody = true
+	}
+
+	e.Handler = func(w http.ResponseWriter, r *http.Request) {
+		in := reflect.New(inType)
+		if hasParams {
+			if err := e.Bind(r, in.Interface()); err != nil {
+				e.respondError(http.StatusBadRequest, "request parameters are invalid", err, w, r)
+				return
+			}
+		}
+		if body := Body(r); bodyType != nil && body != nil {
+			target := in.Elem()
+			if bodyIndex != nil {
+				target = target.FieldByIndex(bodyIndex)
+			}
+			target.Set(reflect.ValueOf(body))
+		}
+		out, err := h(r.Context(), in.Elem().Interface().(In))
+		if err != nil {
+			e.handleError(err, w, r)
+			return
+		}
+		var result interface{} = out
+		if e.Filtering != nil && reflect.ValueOf(out).Kind() == reflect.Slice {
+			query, err := e.ParseQuery(r)
+			if err != nil {
+				e.respondError(http.StatusBadRequest, "request parameters are invalid", err, w, r)
+				return
+			}
+			result, _ = query.Apply(out)
+		}
+		if e.Pagination != nil && reflect.ValueOf(result).Kind() == reflect.Slice {
+			e.RespondPage(w, r, result)
+			return
+		}
+		respond.Auto(w, r, http.StatusOK, result)
+	}
+	return e
+}
+
+// inputLayout inspects the input type of a typed handler. It returns the index
+// of the body field (nil if the input itself is the body), the type of the body
+// (nil if there is none) and whether the input holds parameters.
+func inputLayout(t reflect.Type) (bodyIndex []int, bodyType reflect.Type, hasParams bool) {
+	if t.Kind() != reflect.Struct {
+		return nil, t, false
+	}
+	if t.NumField() == 0 {
+		return nil, nil, false
+	}
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		in, ok := field.Tag.Lookup(TagIn)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		tagged = true
+		if in == inBody {
+			bodyIndex, bodyType = field.Index, field.Type
+		} else {
+			hasParams = true
+		}
+	}
+	if !tagged {
+		return nil, t, false
+	}
+	return bodyIndex, bodyType, hasParams
+}
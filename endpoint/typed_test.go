@@ -0,0 +1,110 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type typedTestInput struct {
+	Org   string          `in:"path" name:"org"`
+	Limit int             `in:"query" name:"limit" default:"2"`
+	Body  bodyTestRequest `in:"body"`
+}
+
+type typedTestOutput struct {
+	Org   string `json:"org"`
+	Limit int    `json:"limit"`
+	Name  string `json:"name"`
+}
+
+func TestTypedMetadata(t *testing.T) {
+	ep := Typed(func(ctx context.Context, in typedTestInput) (typedTestOutput, error) {
+		return typedTestOutput{}, nil
+	})
+	if _, ok := ep.RequestBody.(bodyTestRequest); !ok {
+		t.Errorf("request body is not as expected, have %T", ep.RequestBody)
+	}
+	if _, ok := ep.Responses[http.StatusOK].(typedTestOutput); !ok {
+		t.Errorf("response is not as expected, have %T", ep.Responses[http.StatusOK])
+	}
+	if len(ep.Parameters) != 2 {
+		t.Errorf("number of parameters is not as expected, have %d, need 2", len(ep.Parameters))
+	}
+
+	bodyOnly := Typed(func(ctx context.Context, in bodyTestRequest) (*typedTestOutput, error) {
+		return nil, nil
+	})
+	if _, ok := bodyOnly.RequestBody.(bodyTestRequest); !ok || len(bodyOnly.Parameters) != 0 {
+		t.Errorf("input without tags is expected to be the request body, have %T", bodyOnly.RequestBody)
+	}
+	if _, ok := bodyOnly.Responses[http.StatusOK].(typedTestOutput); !ok {
+		t.Errorf("pointer output is expected to be documented as value, have %T", bodyOnly.Responses[http.StatusOK])
+	}
+
+	noInput := Typed(func(ctx context.Context, in struct{}) (string, error) { return "", nil })
+	if noInput.RequestBody != nil || noInput.DecodeBody {
+		t.Errorf("empty input is not expected to have a request body, have %T", noInput.RequestBody)
+	}
+}
+
+func TestTypedHandler(t *testing.T) {
+	cases := map[string]struct {
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"Valid request": {
+			path:           "/orgs/acme/?limit=5",
+			body:           `{"name": "todo"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"limit": 5`,
+		},
+		"Default parameter": {
+			path:           "/orgs/acme/",
+			body:           `{"name": "todo"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"limit": 2`,
+		},
+		"Invalid parameter": {
+			path:           "/orgs/acme/?limit=many",
+			body:           `{"name": "todo"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "'limit' must be an integer",
+		},
+		"Invalid body": {
+			path:           "/orgs/acme/",
+			body:           `{"name": "t"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "'name' must be at least 3 characters long",
+		},
+		"Handler error": {
+			path:           "/orgs/fail/",
+			body:           `{"name": "todo"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error",
+		},
+	}
+	ep := Typed(func(ctx context.Context, in typedTestInput) (typedTestOutput, error) {
+		if in.Org == "fail" {
+			return typedTestOutput{}, fmt.Errorf("failed")
+		}
+		return typedTestOutput{Org: in.Org, Limit: in.Limit, Name: in.Body.Name}, nil
+	})
+	router := routerWithEndpoint(t, "/orgs/{org}/", http.MethodPost, ep)
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			w := serve(router, httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body)))
+			if w.Code != c.expectedStatus {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, c.expectedStatus)
+			}
+			if !strings.Contains(w.Body.String(), c.expectedBody) {
+				t.Errorf("body %q does not contain %q", w.Body.String(), c.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ValidateJSON checks the data provided against the schema. data is expected
+// to be the result of decoding a JSON document into an interface{}, references
+// in the schema are resolved against the definitions of the schema itself as
+// generated by [jsonschema.Reflect]. All violations found are returned.
+//
+// Only the subset of the JSON Schema vocabulary emitted by [jsonschema.Reflect]
+// is supported: type, enum, const, required, properties, additionalProperties,
+// patternProperties, items, length, pattern, format, numeric bounds and the
+// allOf, anyOf and oneOf combinators. Schemas marked 'nullable' as done by
+// [ReflectSchema] accept null in addition.
+func ValidateJSON(schema *jsonschema.Schema, data interface{}) Violations {
+	v := &validator{root: schema}
+	v.validate(schema, data, "")
+	return v.violations
+}
+
+// ValidateValue marshals v to JSON and validates the result using [ValidateJSON]
+// against the schema reflected from v itself.
+func ValidateValue(v interface{}) (Violations, error) {
+	data, err := asJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateJSON(ReflectSchema(v), data), nil
+}
+
+// ReflectSchema returns the JSON schema of v using [jsonschema.Reflect]. As Go
+// renders nil slices, maps and pointers as null, the schemas of values of these
+// kinds are marked 'nullable' according to the [OpenAPI Specification].
+//
+// [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#schemaObject
+func ReflectSchema(v interface{}) *jsonschema.Schema {
+	schema := jsonschema.Reflect(v)
+	m := nullableMarker{defs: schema.Definitions, seen: map[reflect.Type]bool{}}
+	m.mark(schema, reflect.TypeOf(v))
+	return schema
+}
+
+type nullableMarker struct {
+	defs jsonschema.Definitions
+	seen map[reflect.Type]bool
+}
+
+// mark marks the schema s of values of type t as well as the schemas nested
+// within as 'nullable' if their values are rendered as null when nil.
+func (m nullableMarker) mark(s *jsonschema.Schema, t reflect.Type) {
+	if s == nil || t == nil || s == jsonschema.TrueSchema || s == jsonschema.FalseSchema {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if s.Extras == nil {
+			s.Extras = map[string]interface{}{}
+		}
+		s.Extras["nullable"] = true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			m.mark(s.Items, t.Elem())
+		}
+	case reflect.Map:
+		for _, sub := range s.PatternProperties {
+			m.mark(sub, t.Elem())
+		}
+	case reflect.Struct:
+		if s.Ref == "" || m.seen[t] {
+			return
+		}
+		m.seen[t] = true
+		if def, ok := m.defs[s.Ref[strings.LastIndex(s.Ref, "/")+1:]]; ok {
+			m.fields(def, t)
+		}
+	}
+}
+
+// fields marks the properties of the schema of the struct type t, the fields
+// of embedded structs are treated as fields of t.
+func (m nullableMarker) fields(s *jsonschema.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			m.fields(s, f.Type)
+			continue
+		}
+		if !f.IsExported() || name == "-" || s.Properties == nil {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if prop, ok := s.Properties.Get(name); ok {
+			if sub, ok := prop.(*jsonschema.Schema); ok {
+				m.mark(sub, f.Type)
+			}
+		}
+	}
+}
+
+// asJSON returns v as if it was decoded from its JSON representation.
+func asJSON(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %T: %w", v, err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %T: %w", v, err)
+	}
+	return data, nil
+}
+
+type validator struct {
+	root       *jsonschema.Schema
+	violations Violations
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "."
+	}
+	v.violations = append(v.violations, Violation{Field: path, Location: "body", Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) resolve(s *jsonschema.Schema) *jsonschema.Schema {
+	for s != nil && s.Ref != "" {
+		name := s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+		def, ok := v.root.Definitions[name]
+		if !ok {
+			return nil
+		}
+		s = def
+	}
+	return s
+}
+
+func (v *validator) valid(s *jsonschema.Schema, data interface{}, path string) bool {
+	sub := &validator{root: v.root}
+	sub.validate(s, data, path)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(s *jsonschema.Schema, data interface{}, path string) {
+	if data == nil && s != nil && s.Extras["nullable"] == true {
+		return
+	}
+	s = v.resolve(s)
+	if s == nil || s == jsonschema.TrueSchema {
+		return
+	}
+	if s == jsonschema.FalseSchema {
+		v.fail(path, "is not allowed")
+		return
+	}
+	for _, sub := range s.AllOf {
+		v.validate(sub, data, path)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if v.valid(sub, data, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any of the allowed schemas")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if v.valid(sub, data, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.fail(path, "must match exactly one of the allowed schemas")
+		}
+	}
+	if s.Type != "" && !hasJSONType(s.Type, data) {
+		v.fail(path, "must be of type %s", s.Type)
+		return
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if jsonEqual(e, data) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "must be one of %v", s.Enum)
+		}
+	}
+	if s.Const != nil && !jsonEqual(s.Const, data) {
+		v.fail(path, "must be %v", s.Const)
+	}
+	switch d := data.(type) {
+	case map[string]interface{}:
+		v.validateObject(s, d, path)
+	case []interface{}:
+		v.validateArray(s, d, path)
+	case string:
+		v.validateString(s, d, path)
+	case float64:
+		v.validateNumber(s, d, path)
+	}
+}
+
+func (v *validator) validateObject(s *jsonschema.Schema, data map[string]interface{}, path string) {
+	for _, name := range s.Required {
+		if _, ok := data[name]; !ok {
+			v.fail(join(path, name), "is required")
+		}
+	}
+	if s.MinProperties > 0 && len(data) < s.MinProperties {
+		v.fail(path, "must have at least %d properties", s.MinProperties)
+	}
+	if s.MaxProperties > 0 && len(data) > s.MaxProperties {
+		v.fail(path, "must have at most %d properties", s.MaxProperties)
+	}
+	for name, value := range data {
+		matched := false
+		if s.Properties != nil {
+			if prop, ok := s.Properties.Get(name); ok {
+				matched = true
+				if propSchema, ok := prop.(*jsonschema.Schema); ok {
+					v.validate(propSchema, value, join(path, name))
+				}
+			}
+		}
+		for pattern, sub := range s.PatternProperties {
+			if rex, err := regexp.Compile(pattern); err == nil && rex.MatchString(name) {
+				matched = true
+				v.validate(sub, value, join(path, name))
+			}
+		}
+		if !matched && s.AdditionalProperties != nil {
+			if s.AdditionalProperties == jsonschema.FalseSchema {
+				v.fail(join(path, name), "is not allowed")
+				continue
+			}
+			v.validate(s.AdditionalProperties, value, join(path, name))
+		}
+	}
+}
+
+func (v *validator) validateArray(s *jsonschema.Schema, data []interface{}, path string) {
+	if s.MinItems > 0 && len(data) < s.MinItems {
+		v.fail(path, "must contain at least %d items", s.MinItems)
+	}
+	if s.MaxItems > 0 && len(data) > s.MaxItems {
+		v.fail(path, "must contain at most %d items", s.MaxItems)
+	}
+	if s.UniqueItems {
+		for i := range data {
+			for j := i + 1; j < len(data); j++ {
+				if jsonEqual(data[i], data[j]) {
+					v.fail(path, "must contain unique items")
+					i = len(data)
+					break
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range data {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+var formats = map[string]func(string) bool{
+	"date-time": func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+	"date":      func(s string) bool { _, err := time.Parse("2006-01-02", s); return err == nil },
+	"email":     regexp.MustCompile(`^[^@\s]+@[^@\s]+$`).MatchString,
+	"uuid":      regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+func (v *validator) validateString(s *jsonschema.Schema, data string, path string) {
+	length := utf8.RuneCountInString(data)
+	if s.MinLength > 0 && length < s.MinLength {
+		v.fail(path, "must be at least %d characters long", s.MinLength)
+	}
+	if s.MaxLength > 0 && length > s.MaxLength {
+		v.fail(path, "must be at most %d characters long", s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if rex, err := compilePattern(s.Pattern); err == nil && !rex.MatchString(data) {
+			v.fail(path, "must match pattern '%s'", s.Pattern)
+		}
+	}
+	if check, ok := formats[s.Format]; ok && !check(data) {
+		v.fail(path, "must be a valid %s", s.Format)
+	}
+}
+
+func (v *validator) validateNumber(s *jsonschema.Schema, data float64, path string) {
+	if s.Minimum != 0 || s.ExclusiveMinimum {
+		lower := float64(s.Minimum)
+		if data < lower || (s.ExclusiveMinimum && data == lower) {
+			v.fail(path, "must be greater %s %d", orEqual(s.ExclusiveMinimum), s.Minimum)
+		}
+	}
+	if s.Maximum != 0 || s.ExclusiveMaximum {
+		upper := float64(s.Maximum)
+		if data > upper || (s.ExclusiveMaximum && data == upper) {
+			v.fail(path, "must be less %s %d", orEqual(s.ExclusiveMaximum), s.Maximum)
+		}
+	}
+	if s.MultipleOf > 0 && math.Mod(data, float64(s.MultipleOf)) != 0 {
+		v.fail(path, "must be a multiple of %d", s.MultipleOf)
+	}
+}
+
+func orEqual(exclusive bool) string {
+	if exclusive {
+		return "than"
+	}
+	return "than or equal to"
+}
+
+func hasJSONType(t string, data interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := data.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := data.([]interface{})
+		return ok
+	case "string":
+		_, ok := data.(string)
+		return ok
+	case "boolean":
+		_, ok := data.(bool)
+		return ok
+	case "number":
+		_, ok := data.(float64)
+		return ok
+	case "integer":
+		f, ok := data.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return data == nil
+	}
+	return true
+}
+
+func jsonEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", path, name)
+}
@@ -0,0 +1,159 @@
+package endpoint
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// VersionPlaceholder is used in paths passed to [Endpoints.AddVersioned] to
+// version endpoints by path. The placeholder is replaced by the version, such
+// as '/api/{version}/todos/' becoming '/api/v1/todos/'.
+const VersionPlaceholder = "{version}"
+
+// VersionScheme defines how the version requested by a client is determined for
+// endpoints sharing a path across versions.
+type VersionScheme int
+
+const (
+	VersionByHeader VersionScheme = iota // the version is provided in a custom header, such as 'API-Version: v1'
+	VersionByAccept                      // the version is a parameter of the 'accept' media type, such as 'application/json; version=v1'
+)
+
+// Versioning configures how requests are dispatched to the versions of the
+// endpoints added via [Endpoints.AddVersioned] without [VersionPlaceholder].
+// Endpoints versioned by path are routed by their path alone.
+type Versioning struct {
+	// Scheme defines how the version of a request is determined.
+	Scheme VersionScheme
+	// Parameter is the name of the media type parameter holding the version when
+	// versioning by 'accept' header, defaults to 'version'.
+	Parameter string
+	// Header is the name of the header holding the version when versioning by
+	// header, defaults to 'API-Version'.
+	Header string
+	// Default is the version used for requests which do not specify a version.
+	Default string
+}
+
+// AddVersioned adds an [Endpoint] for each of the versions provided. If the
+// path contains the [VersionPlaceholder], it is replaced by the version and
+// each version is served at a path of its own. Otherwise all versions share the
+// path and requests are dispatched by the version they specify according to the
+// Versioning of the [Config] passed to [Endpoints.Populate].
+func (c *Endpoints) AddVersioned(path, method string, e *Endpoint, versions ...string) error {
+	return c.addVersioned(path, method, e, TrailingSlashAppend, versions...)
+}
+
+func (c *Endpoints) addVersioned(path, method string, e *Endpoint, policy TrailingSlash, versions ...string) error {
+	if len(versions) == 0 {
+		return fmt.Errorf("cannot add endpoint '%s' without versions", e.Name)
+	}
+	for _, version := range versions {
+		if err := c.add(path, method, version, e, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Versions returns all versions of the collection, sorted.
+func (c Endpoints) Versions() []string {
+	out := []string{}
+	for caller := range c {
+		if caller.Version != "" && !contains(out, caller.Version) {
+			out = append(out, caller.Version)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Version returns a collection holding the endpoints of the version provided as
+// well as all endpoints not added via [Endpoints.AddVersioned]. The version of
+// the [Caller]s of the collection returned is not set, which allows to treat it
+// as an unversioned collection, for example to generate an OpenAPI document.
+// Versions of an endpoint take precedence over the unversioned endpoint of the
+// same path and method.
+func (c Endpoints) Version(version string) Endpoints {
+	out := Endpoints{}
+	for caller, e := range c {
+		if caller.Version != version {
+			continue
+		}
+		caller.Version = ""
+		out[caller] = e
+	}
+	for caller, e := range c {
+		if _, exists := out[caller]; caller.Version == "" && !exists {
+			out[caller] = e
+		}
+	}
+	return out
+}
+
+// version returns the version requested, the Default if none is requested.
+func (v Versioning) version(r *http.Request) string {
+	version := ""
+	switch v.Scheme {
+	case VersionByAccept:
+		param := v.Parameter
+		if param == "" {
+			param = "version"
+		}
+		for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+			_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err == nil && params[param] != "" {
+				version = params[param]
+				break
+			}
+		}
+	case VersionByHeader:
+		header := v.Header
+		if header == "" {
+			header = "API-Version"
+		}
+		version = r.Header.Get(header)
+	}
+	if version == "" {
+		return v.Default
+	}
+	return version
+}
+
+// versioned is a handler of a version of an endpoint.
+type versioned struct {
+	version string
+	handler http.HandlerFunc
+}
+
+// dispatchVersion returns a handler which dispatches requests to the handler
+// of the version requested. Handlers without a version serve all requests not
+// served by a specific version.
+func (c Endpoints) dispatchVersion(path string, handlers []versioned, cfg Config) http.HandlerFunc {
+	rep := c.representative(path)
+	return func(w http.ResponseWriter, r *http.Request) {
+		version := cfg.Versioning.version(r)
+		var fallback http.HandlerFunc
+		for _, h := range handlers {
+			if h.version == version {
+				h.handler(w, r)
+				return
+			}
+			if h.version == "" {
+				fallback = h.handler
+			}
+		}
+		if fallback != nil {
+			fallback(w, r)
+			return
+		}
+		if version == "" {
+			rep.respondError(http.StatusBadRequest, "API version is required", nil, w, r)
+			return
+		}
+		rep.respondError(http.StatusNotFound, fmt.Sprintf("not available in API version '%s'", version), nil, w, r)
+	}
+}
@@ -0,0 +1,195 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAddVersioned(t *testing.T) {
+	cases := map[string]struct {
+		versioning Versioning
+		method     string
+		path       string
+		header     http.Header
+		status     int
+		body       string
+	}{
+		"Version in path": {
+			method: http.MethodGet,
+			path:   "/v2/items/",
+			status: http.StatusOK,
+			body:   "items v2",
+		},
+		"Unknown version in path": {
+			method: http.MethodGet,
+			path:   "/v3/items/",
+			status: http.StatusNotFound,
+		},
+		"Version in header": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			header:     http.Header{"Api-Version": {"v1"}},
+			status:     http.StatusOK,
+			body:       "list v1",
+		},
+		"Version in custom header": {
+			versioning: Versioning{Scheme: VersionByHeader, Header: "X-Version"},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			header:     http.Header{"X-Version": {"v2"}},
+			status:     http.StatusOK,
+			body:       "list v2",
+		},
+		"Version in header with head": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodHead,
+			path:       "/todos/",
+			header:     http.Header{"Api-Version": {"v2"}},
+			status:     http.StatusOK,
+		},
+		"Version in accept parameter": {
+			versioning: Versioning{Scheme: VersionByAccept},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			header:     http.Header{"Accept": {"text/plain, application/json; version=v2"}},
+			status:     http.StatusOK,
+			body:       "list v2",
+		},
+		"Default version": {
+			versioning: Versioning{Scheme: VersionByAccept, Default: "v1"},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			status:     http.StatusOK,
+			body:       "list v1",
+		},
+		"Missing version": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			status:     http.StatusBadRequest,
+		},
+		"Unknown version": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodGet,
+			path:       "/todos/",
+			header:     http.Header{"Api-Version": {"v3"}},
+			status:     http.StatusNotFound,
+		},
+		"Method only available in other version": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodDelete,
+			path:       "/todos/",
+			header:     http.Header{"Api-Version": {"v1"}},
+			status:     http.StatusNotFound,
+		},
+		"Unversioned endpoint": {
+			versioning: Versioning{Scheme: VersionByHeader},
+			method:     http.MethodGet,
+			path:       "/health/",
+			header:     http.Header{"Api-Version": {"v2"}},
+			status:     http.StatusOK,
+			body:       "ok",
+		},
+	}
+
+	for routerName, newRouter := range routers() {
+		for name, tc := range cases {
+			t.Run(routerName+" "+name, func(t *testing.T) {
+				endpoints := Endpoints{}
+				for _, version := range []string{"v1", "v2"} {
+					list, items := "list "+version, "items "+version
+					err := endpoints.AddVersioned("/todos/", http.MethodGet, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {
+						fmt.Fprint(w, list)
+					}}, version)
+					if err != nil {
+						t.Fatalf("adding version %s failed: %s", version, err)
+					}
+					err = endpoints.AddVersioned("/{version}/items/", http.MethodGet, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {
+						fmt.Fprint(w, items)
+					}}, version)
+					if err != nil {
+						t.Fatalf("adding version %s failed: %s", version, err)
+					}
+				}
+				endpoints.AddVersioned("/todos/", http.MethodDelete, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {}}, "v2")
+				endpoints.Add("/health/", http.MethodGet, &Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, "ok")
+				}})
+
+				handler, router := newRouter()
+				if err := endpoints.Populate(router, Config{Versioning: tc.versioning}); err != nil {
+					t.Fatalf("populating the router failed: %s", err)
+				}
+				r := httptest.NewRequest(tc.method, tc.path, nil)
+				for key, values := range tc.header {
+					r.Header[key] = values
+				}
+				w := serve(handler, r)
+				if w.Code != tc.status {
+					t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+				}
+				if tc.body != "" && w.Body.String() != tc.body {
+					t.Errorf("body is not as expected, have %q, need %q", w.Body.String(), tc.body)
+				}
+			})
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	endpoints := Endpoints{}
+	endpoints.AddVersioned("/api/{version}/todos/", http.MethodGet, &Endpoint{Name: "list"}, "v1", "v2")
+	endpoints.AddVersioned("/api/{version}/todos/", http.MethodDelete, &Endpoint{Name: "delete"}, "v2")
+	endpoints.AddVersioned("/status/", http.MethodGet, &Endpoint{Name: "status v2"}, "v2")
+	endpoints.Add("/status/", http.MethodGet, &Endpoint{Name: "status"})
+	endpoints.Add("/health/", http.MethodGet, &Endpoint{Name: "health"})
+
+	if versions := endpoints.Versions(); !reflect.DeepEqual(versions, []string{"v1", "v2"}) {
+		t.Errorf("versions are not as expected, have %v", versions)
+	}
+	cases := map[string]map[Caller]string{
+		"v1": {
+			{Path: "/api/v1/todos/", Method: http.MethodGet}: "list",
+			{Path: "/health/", Method: http.MethodGet}:       "health",
+			{Path: "/status/", Method: http.MethodGet}:       "status",
+		},
+		"v2": {
+			{Path: "/api/v2/todos/", Method: http.MethodGet}:    "list",
+			{Path: "/api/v2/todos/", Method: http.MethodDelete}: "delete",
+			{Path: "/health/", Method: http.MethodGet}:          "health",
+			{Path: "/status/", Method: http.MethodGet}:          "status v2",
+		},
+	}
+	for version, expected := range cases {
+		names := map[Caller]string{}
+		for caller, e := range endpoints.Version(version) {
+			names[caller] = e.Name
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("endpoints of version %s are not as expected, have %v, need %v", version, names, expected)
+		}
+	}
+	if err := endpoints.AddVersioned("/api/{version}/todos/", http.MethodGet, &Endpoint{}, "v2"); err == nil {
+		t.Errorf("adding an existing version is expected to fail")
+	}
+	if err := endpoints.AddVersioned("/todos/", http.MethodGet, &Endpoint{}); err == nil {
+		t.Errorf("adding an endpoint without versions is expected to fail")
+	}
+}
+
+func TestVersionedOperationNames(t *testing.T) {
+	endpoints := Endpoints{}
+	endpoints.AddVersioned("/todos/", http.MethodGet, &Endpoint{Name: "list"}, "v1", "v2")
+	if err := endpoints.Validate(); err != nil {
+		t.Errorf("names are expected to be unique per version, have %s", err.Error())
+	}
+	endpoints.AddVersioned("/items/", http.MethodGet, &Endpoint{Name: "list"}, "v2")
+	expected := "operation name 'list' is used by GET /items/, GET /todos/ in version 'v2'"
+	if err := endpoints.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("conflict is not as expected, have %v, need %q", err, expected)
+	}
+}
@@ -0,0 +1,364 @@
+// Package endpointtest provides a harness to test an [github.com/unprofession-al/httpthings/endpoint.Endpoints]
+// collection against the contract published via package `openapi`. Each endpoint
+// is called with the examples it declares, the responses are expected to use a
+// documented status code and to match the schema published for that status.
+// Use [Test] in a regular go test to fail on contract drift:
+//
+//	func TestContract(t *testing.T) {
+//		endpointtest.Test(t, server.Endpoints())
+//	}
+package endpointtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/iancoleman/orderedmap"
+	"github.com/invopop/jsonschema"
+	"github.com/unprofession-al/httpthings/endpoint"
+	"github.com/unprofession-al/httpthings/openapi"
+)
+
+// Harness exercises all endpoints of a collection, see [Harness.Check]. The zero
+// value is ready to use.
+type Harness struct {
+	// Prepare is called with each request before it is sent, for example to add
+	// the credentials required by the Auth of the endpoints.
+	Prepare func(r *http.Request)
+	// Router returns the router the endpoints are populated to along with the
+	// handler serving it, a [github.com/gorilla/mux.Router] is used if not set.
+	// Use it to test the endpoints with the router used in production.
+	Router func() (http.Handler, endpoint.Router)
+	// Config is passed to [github.com/unprofession-al/httpthings/endpoint.Endpoints.Populate],
+	// its Versioning is used to request the version of versioned endpoints.
+	Config endpoint.Config
+}
+
+// Check exercises the endpoints provided using a zero [Harness].
+func Check(c endpoint.Endpoints) (Report, error) {
+	return Harness{}.Check(c)
+}
+
+// Test exercises the endpoints provided using a zero [Harness] and fails the
+// test if the contract is violated.
+func Test(t testing.TB, c endpoint.Endpoints) Report {
+	t.Helper()
+	return Harness{}.Test(t, c)
+}
+
+// Test calls [Harness.Check] and fails the test with the [Report] if the
+// contract is violated. Endpoints skipped are logged.
+func (h Harness) Test(t testing.TB, c endpoint.Endpoints) Report {
+	t.Helper()
+	report, err := h.Check(c)
+	if err != nil {
+		t.Fatalf("contract could not be checked: %s", err)
+	}
+	if report.Failed() {
+		t.Errorf("%s", report)
+	} else if len(report.Skipped) > 0 {
+		t.Logf("%s", report)
+	}
+	return report
+}
+
+// Check populates the Router of the harness with the endpoints provided, serves
+// it using a [net/http/httptest.Server] and calls each endpoint which is not hidden
+// once per example of its request body, or once if it does not expect a body.
+// Parameters are set to the example of the same name as the body example or to
+// their first example otherwise. Required parameters without examples are set to
+// their default, endpoints for which no request can be built are skipped.
+//
+// Each response is checked against the operation published by
+// [github.com/unprofession-al/httpthings/openapi.FromEndpoints]: the status code
+// and the media type of the body must be documented. Bodies are validated
+// against the schema of the documented response only if they are JSON, bodies
+// of other media types such as YAML are not validated. An error is returned if
+// the endpoints cannot be populated, contract violations are reported in the
+// [Report].
+func (h Harness) Check(c endpoint.Endpoints) (Report, error) {
+	newRouter := h.Router
+	if newRouter == nil {
+		newRouter = func() (http.Handler, endpoint.Router) {
+			r := mux.NewRouter()
+			return r, endpoint.MuxRouter(r)
+		}
+	}
+	handler, router := newRouter()
+	if err := c.Populate(router, h.Config); err != nil {
+		return Report{}, fmt.Errorf("endpoints could not be populated: %w", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	report := Report{}
+	docs := map[string]openapi.Doc{}
+	for _, caller := range callers(c) {
+		e := c[caller]
+		if e.Hidden {
+			continue
+		}
+		doc, ok := docs[caller.Version]
+		if !ok {
+			doc = openapi.FromEndpoints(c.Version(caller.Version))
+			docs[caller.Version] = doc
+		}
+		op := operation(doc.Paths[strings.ReplaceAll(caller.Path, "...}", "}")], caller.Method)
+		for _, example := range bodyExamples(e) {
+			r, err := h.request(srv.URL, caller, e, example)
+			if err != nil {
+				report.Skipped = append(report.Skipped, Finding{Caller: caller, Example: example, Message: err.Error()})
+				continue
+			}
+			res, err := srv.Client().Do(r)
+			if err != nil {
+				return report, fmt.Errorf("%s %s could not be called: %w", caller.Method, caller.Path, err)
+			}
+			body, err := io.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				return report, fmt.Errorf("response of %s %s could not be read: %w", caller.Method, caller.Path, err)
+			}
+			report.Requests++
+			for _, message := range verify(doc, op, res, body) {
+				report.Violations = append(report.Violations, Finding{
+					Caller:  caller,
+					Example: example,
+					Status:  res.StatusCode,
+					Message: message,
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+// callers returns the callers of the collection sorted by path, method and
+// version.
+func callers(c endpoint.Endpoints) []endpoint.Caller {
+	out := make([]endpoint.Caller, 0, len(c))
+	for caller := range c {
+		out = append(out, caller)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
+
+// bodyExamples returns the names of the examples of the request body of the
+// endpoint, a single empty name if the endpoint does not expect a body.
+func bodyExamples(e *endpoint.Endpoint) []string {
+	if e.RequestBody == nil || len(e.Examples.RequestBody) == 0 {
+		return []string{""}
+	}
+	out := make([]string, 0, len(e.Examples.RequestBody))
+	for name := range e.Examples.RequestBody {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// request builds the request calling the endpoint with the example provided.
+// An error is returned if the request cannot be built from the examples.
+func (h Harness) request(base string, caller endpoint.Caller, e *endpoint.Endpoint, example string) (*http.Request, error) {
+	var body io.Reader
+	if e.RequestBody != nil {
+		if example == "" {
+			return nil, fmt.Errorf("no example of the request body")
+		}
+		raw, err := json.Marshal(e.Examples.RequestBody[example].Value)
+		if err != nil {
+			return nil, fmt.Errorf("example '%s' of the request body could not be marshalled: %w", example, err)
+		}
+		body = bytes.NewReader(raw)
+	}
+	path := strings.TrimSuffix(caller.Path, "*/")
+	query := url.Values{}
+	header := http.Header{}
+	cookies := []*http.Cookie{}
+	for _, p := range e.Parameters {
+		val, ok := parameterValue(e, p, example)
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("no example of the required %s parameter '%s'", p.Location, p.Name)
+			}
+			continue
+		}
+		switch p.Location {
+		case endpoint.ParameterLocationPath:
+			path = strings.Replace(path, "{"+p.Name+"...}", val, 1)
+			path = strings.Replace(path, "{"+p.Name+"}", url.PathEscape(val), 1)
+		case endpoint.ParameterLocationQuery:
+			query.Set(p.Name, val)
+		case endpoint.ParameterLocationHeader:
+			header.Set(p.Name, val)
+		case endpoint.ParameterLocationCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: val})
+		}
+	}
+	target := base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	r, err := http.NewRequest(caller.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	r.Header = header
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	if body != nil {
+		contentType := "application/json"
+		if mt, ok := e.RequestBody.(endpoint.MediaTyper); ok {
+			contentType = mt.MediaType()
+		}
+		r.Header.Set("Content-Type", contentType)
+	}
+	r.Header.Set("Accept", "application/json")
+	if versioning := h.Config.Versioning; caller.Version != "" {
+		switch versioning.Scheme {
+		case endpoint.VersionByAccept:
+			param := versioning.Parameter
+			if param == "" {
+				param = "version"
+			}
+			r.Header.Set("Accept", fmt.Sprintf("application/json; %s=%s", param, caller.Version))
+		case endpoint.VersionByHeader:
+			name := versioning.Header
+			if name == "" {
+				name = "API-Version"
+			}
+			r.Header.Set(name, caller.Version)
+		}
+	}
+	if h.Prepare != nil {
+		h.Prepare(r)
+	}
+	return r, nil
+}
+
+// parameterValue returns the value of the parameter for the example provided.
+// The example of the same name is preferred over the first example. Required
+// parameters without examples use their default.
+func parameterValue(e *endpoint.Endpoint, p endpoint.Parameter, example string) (string, bool) {
+	examples := e.Examples.Parameters[p.Name]
+	if ex, ok := examples[example]; ok {
+		val, ok := ex.Value.(string)
+		return val, ok
+	}
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		val, ok := examples[names[0]].Value.(string)
+		return val, ok
+	}
+	if p.Required && p.Default != "" {
+		return p.Default, true
+	}
+	return "", false
+}
+
+// verify checks the response against the operation documented and returns a
+// message for each violation of the contract.
+func verify(doc openapi.Doc, op *openapi.Operation, res *http.Response, body []byte) []string {
+	if op == nil {
+		return []string{"operation is not documented"}
+	}
+	documented, ok := op.Responses[strconv.Itoa(res.StatusCode)]
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented", res.StatusCode)}
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return []string{fmt.Sprintf("media type of the %d response is invalid: %s", res.StatusCode, err)}
+	}
+	content, ok := documented.Content[mediaType]
+	if !ok {
+		return []string{fmt.Sprintf("media type '%s' of the %d response is not documented", mediaType, res.StatusCode)}
+	}
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		// the schemas published describe JSON, other bodies cannot be validated
+		return nil
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return []string{fmt.Sprintf("body of the %d response is not valid JSON: %s", res.StatusCode, err)}
+	}
+	schema := jsonSchema(content.Schema)
+	schema.Definitions = doc.Components.Schemas
+	if violations := endpoint.ValidateJSON(schema, data); len(violations) > 0 {
+		return []string{fmt.Sprintf("body of the %d response does not match the schema: %s", res.StatusCode, violations)}
+	}
+	return nil
+}
+
+// jsonSchema converts a schema of the OpenAPI document in order to validate
+// data using [github.com/unprofession-al/httpthings/endpoint.ValidateJSON].
+func jsonSchema(s openapi.Schema) *jsonschema.Schema {
+	out := &jsonschema.Schema{
+		Type:    s.Type,
+		Format:  s.Format,
+		Ref:     s.Ref,
+		Enum:    s.Enum,
+		Pattern: s.Pattern,
+	}
+	if s.Items != nil {
+		out.Items = jsonSchema(*s.Items)
+	}
+	if len(s.Properties) > 0 {
+		out.Properties = orderedmap.New()
+		for name, prop := range s.Properties {
+			out.Properties.Set(name, jsonSchema(prop))
+		}
+	}
+	return out
+}
+
+// operation returns the operation of the path item for the method provided.
+func operation(item openapi.PathItem, method string) *openapi.Operation {
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodHead:
+		return item.Head
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodTrace:
+		return item.Trace
+	}
+	return nil
+}
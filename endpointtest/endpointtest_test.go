@@ -0,0 +1,247 @@
+package endpointtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/unprofession-al/httpthings/endpoint"
+	"github.com/unprofession-al/httpthings/respond"
+)
+
+type todo struct {
+	Name string `json:"name" jsonschema:"minLength=3"`
+	Done bool   `json:"done"`
+}
+
+func TestCheck(t *testing.T) {
+	show := func(handler http.HandlerFunc) *endpoint.Endpoint {
+		return &endpoint.Endpoint{
+			Responses: map[int]interface{}{http.StatusOK: todo{}},
+			Examples: endpoint.Examples{
+				Parameters: map[string]map[string]endpoint.Example{"name": {"shopping": {Value: "shopping"}}},
+			},
+			Handler: handler,
+		}
+	}
+	cases := map[string]struct {
+		path       string
+		method     string
+		endpoint   *endpoint.Endpoint
+		requests   int
+		violations []string
+		skipped    []string
+	}{
+		"Response matching the contract": {
+			path:   "/todos/{name}/",
+			method: http.MethodGet,
+			endpoint: func() *endpoint.Endpoint {
+				e := show(nil)
+				e.Handler = func(w http.ResponseWriter, r *http.Request) {
+					name, _ := e.GetParamAsString("name", r)
+					respond.JSON(w, http.StatusOK, todo{Name: name})
+				}
+				return e
+			}(),
+			requests: 1,
+		},
+		"Undocumented status": {
+			path:   "/todos/{name}/",
+			method: http.MethodGet,
+			endpoint: show(func(w http.ResponseWriter, r *http.Request) {
+				respond.JSON(w, http.StatusTeapot, todo{Name: "teapot"})
+			}),
+			requests:   1,
+			violations: []string{"GET /todos/{name}/: status 418 is not documented"},
+		},
+		"Body violating the schema": {
+			path:   "/todos/{name}/",
+			method: http.MethodGet,
+			endpoint: show(func(w http.ResponseWriter, r *http.Request) {
+				respond.JSON(w, http.StatusOK, map[string]interface{}{"name": "shopping", "done": "no"})
+			}),
+			requests: 1,
+			violations: []string{
+				"GET /todos/{name}/: body of the 200 response does not match the schema: body 'done' must be of type boolean",
+			},
+		},
+		"Undocumented media type": {
+			path:   "/todos/{name}/",
+			method: http.MethodGet,
+			endpoint: show(func(w http.ResponseWriter, r *http.Request) {
+				respond.YAML(w, http.StatusOK, todo{Name: "shopping"})
+			}),
+			requests:   1,
+			violations: []string{"GET /todos/{name}/: media type 'text/yaml' of the 200 response is not documented"},
+		},
+		"Request per body example": {
+			path:   "/todos/",
+			method: http.MethodPost,
+			endpoint: &endpoint.Endpoint{
+				RequestBody: todo{},
+				DecodeBody:  true,
+				Responses:   map[int]interface{}{http.StatusCreated: todo{}},
+				Examples: endpoint.Examples{
+					RequestBody: map[string]endpoint.Example{
+						"laundry":  {Value: todo{Name: "laundry"}},
+						"shopping": {Value: todo{Name: "shopping"}},
+					},
+				},
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					in := endpoint.Body(r).(todo)
+					if in.Name == "laundry" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					respond.JSON(w, http.StatusCreated, in)
+				},
+			},
+			requests:   2,
+			violations: []string{"POST /todos/ with example 'laundry': status 500 is not documented"},
+		},
+		"Paginated response": {
+			path:   "/todos/",
+			method: http.MethodGet,
+			endpoint: func() *endpoint.Endpoint {
+				e := endpoint.Typed(func(ctx context.Context, in struct{}) ([]todo, error) {
+					return []todo{{Name: "shopping"}, {Name: "laundry"}}, nil
+				})
+				e.Paginate(endpoint.Pagination{DefaultLimit: 1})
+				return e
+			}(),
+			requests: 1,
+		},
+		"Missing body example": {
+			path:   "/todos/",
+			method: http.MethodPost,
+			endpoint: &endpoint.Endpoint{
+				RequestBody: todo{},
+				Handler:     func(w http.ResponseWriter, r *http.Request) {},
+			},
+			skipped: []string{"POST /todos/: no example of the request body"},
+		},
+		"Missing example of a required parameter": {
+			path:     "/todos/{name}/",
+			method:   http.MethodDelete,
+			endpoint: &endpoint.Endpoint{Handler: func(w http.ResponseWriter, r *http.Request) {}},
+			skipped:  []string{"DELETE /todos/{name}/: no example of the required path parameter 'name'"},
+		},
+	}
+
+	routers := map[string]func() (http.Handler, endpoint.Router){
+		"Gorilla mux": nil,
+		"ServeMux": func() (http.Handler, endpoint.Router) {
+			r := http.NewServeMux()
+			return r, endpoint.ServeMuxRouter(r)
+		},
+	}
+	for routerName, router := range routers {
+		for name, tc := range cases {
+			t.Run(routerName+"/"+name, func(t *testing.T) {
+				endpoints := endpoint.Endpoints{}
+				if err := endpoints.Add(tc.path, tc.method, tc.endpoint); err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+				report, err := Harness{Router: router}.Check(endpoints)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+				if report.Requests != tc.requests {
+					t.Errorf("number of requests is not as expected, have %d, need %d", report.Requests, tc.requests)
+				}
+				for _, check := range []struct {
+					findings []Finding
+					expected []string
+				}{
+					{report.Violations, tc.violations},
+					{report.Skipped, tc.skipped},
+				} {
+					have := []string{}
+					for _, f := range check.findings {
+						have = append(have, f.String())
+					}
+					if strings.Join(have, "\n") != strings.Join(check.expected, "\n") {
+						t.Errorf("findings are not as expected, have %q, need %q", have, check.expected)
+					}
+				}
+				if report.Failed() != (len(tc.violations) > 0) {
+					t.Errorf("report is expected to fail only if violations are found:\n%s", report)
+				}
+			})
+		}
+	}
+}
+
+func TestVersions(t *testing.T) {
+	versioned := func(version string) *endpoint.Endpoint {
+		return &endpoint.Endpoint{
+			Responses: map[int]interface{}{http.StatusOK: todo{}},
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				respond.JSON(w, http.StatusOK, todo{Name: version})
+			},
+		}
+	}
+	endpoints := endpoint.Endpoints{}
+	for _, version := range []string{"v1", "v2"} {
+		if err := endpoints.AddVersioned("/todos/", http.MethodGet, versioned("version "+version), version); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+	if err := endpoints.AddVersioned("/api/{version}/todos/", http.MethodGet, versioned("path"), "v1", "v2"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	cfg := endpoint.Config{Versioning: endpoint.Versioning{Scheme: endpoint.VersionByAccept}}
+	report, err := Harness{Config: cfg}.Check(endpoints)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if report.Requests != 4 || report.Failed() || len(report.Skipped) > 0 {
+		t.Errorf("each version is expected to be requested successfully:\n%s", report)
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Logf(format string, args ...interface{}) {}
+
+func TestHarnessTest(t *testing.T) {
+	endpoints := endpoint.Endpoints{}
+	e := &endpoint.Endpoint{
+		Responses: map[int]interface{}{http.StatusOK: todo{}},
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			respond.JSON(w, http.StatusOK, todo{Name: "shopping"})
+		},
+	}
+	if err := endpoints.Add("/todo/", http.MethodGet, e); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	ft := &fakeT{TB: t}
+	Test(ft, endpoints)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "GET /todo/: status 401 is not documented") {
+		t.Errorf("test is expected to fail with the report, have %q", ft.errors)
+	}
+
+	ft = &fakeT{TB: t}
+	authorized := Harness{Prepare: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") }}
+	authorized.Test(ft, endpoints)
+	if len(ft.errors) != 0 {
+		t.Errorf("test is expected to pass, have %q", ft.errors)
+	}
+}
@@ -0,0 +1,72 @@
+package endpointtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unprofession-al/httpthings/endpoint"
+)
+
+// Finding describes a request of a [Harness] which violated the contract or
+// could not be sent.
+type Finding struct {
+	// Caller identifies the endpoint called.
+	Caller endpoint.Caller
+	// Example is the name of the example of the request body used, empty if the
+	// endpoint does not expect a body.
+	Example string
+	// Status is the status code of the response, 0 if no request was sent.
+	Status  int
+	Message string
+}
+
+// String returns the finding prefixed with the endpoint and the example.
+func (f Finding) String() string {
+	out := fmt.Sprintf("%s %s", f.Caller.Method, f.Caller.Path)
+	if f.Caller.Version != "" {
+		out = fmt.Sprintf("%s (version '%s')", out, f.Caller.Version)
+	}
+	if f.Example != "" {
+		out = fmt.Sprintf("%s with example '%s'", out, f.Example)
+	}
+	return fmt.Sprintf("%s: %s", out, f.Message)
+}
+
+// Report is the result of [Harness.Check].
+type Report struct {
+	// Requests is the number of requests sent.
+	Requests int
+	// Violations lists responses using undocumented status codes or media types
+	// as well as bodies which do not match the schema published.
+	Violations []Finding
+	// Skipped lists the requests which could not be built from the examples.
+	Skipped []Finding
+}
+
+// Failed reports whether any violations of the contract were found.
+func (r Report) Failed() bool {
+	return len(r.Violations) > 0
+}
+
+// String renders the report as a human readable summary followed by the
+// violations and the requests skipped.
+func (r Report) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%d requests sent, %d contract violations, %d skipped", r.Requests, len(r.Violations), len(r.Skipped))
+	for _, section := range []struct {
+		title    string
+		findings []Finding
+	}{
+		{"violations", r.Violations},
+		{"skipped", r.Skipped},
+	} {
+		if len(section.findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:", section.title)
+		for _, f := range section.findings {
+			fmt.Fprintf(&b, "\n  %s", f)
+		}
+	}
+	return b.String()
+}
@@ -1,77 +1,79 @@
 package main
 
 import (
-	"encoding/json"
-	"io"
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/unprofession-al/httpthings/endpoint"
 	"github.com/unprofession-al/httpthings/respond"
 )
 
 func (s Server) ListTodoEndpoint() *endpoint.Endpoint {
-	ep := &endpoint.Endpoint{}
+	ep := endpoint.Typed(func(ctx context.Context, in struct{}) ([]*Todo, error) {
+		return s.todos.AsSlice(), nil
+	})
 	ep.Name = "list-todos"
-	ep.Responses = map[int]interface{}{http.StatusOK: []Todo{}}
-	ep.ErrorResponse = HTTPError{}
-	ep.Auth = s.auth
-	ep.Handler = func(w http.ResponseWriter, r *http.Request) {
-		respond.Auto(w, r, http.StatusOK, s.todos.AsSlice())
+	err := ep.Filterable(endpoint.Filtering{
+		Filter: map[string][]endpoint.FilterOperator{
+			"name": {endpoint.OperatorEq, endpoint.OperatorIn, endpoint.OperatorContains},
+			"done": {endpoint.OperatorEq},
+		},
+		Sort: []string{"name", "done"},
+	})
+	if err != nil {
+		panic(err)
 	}
+	ep.Paginate(endpoint.Pagination{})
 	return ep
 }
 
+type showTodoInput struct {
+	Name string `in:"path" name:"name"`
+}
+
 func (s Server) ShowTodoEndpoint() *endpoint.Endpoint {
-	ep := &endpoint.Endpoint{}
-	ep.Name = "show-todo"
-	ep.Responses = map[int]interface{}{http.StatusOK: Todo{}}
-	ep.ErrorResponse = HTTPError{}
-	errTodoNotProvided := ep.RegisterError(http.StatusNotAcceptable, "todo not provided")
-	errTodoNotFound := ep.RegisterError(http.StatusNotFound, "todo not found")
-	ep.Auth = s.auth
-	ep.Handler = func(w http.ResponseWriter, r *http.Request) {
-		name, ok := ep.GetParamAsString("name", r)
-		if !ok || len(name) < 1 {
-			errTodoNotProvided(w, r)
-			return
+	var errTodoNotFound error
+	ep := endpoint.Typed(func(ctx context.Context, in showTodoInput) (*Todo, error) {
+		todo, found := s.todos[in.Name]
+		if !found {
+			return nil, errTodoNotFound
 		}
-		if todo, found := s.todos[name]; found {
-			respond.Auto(w, r, http.StatusOK, todo)
-			return
-		}
-		errTodoNotFound(w, r)
+		return todo, nil
+	})
+	ep.Name = "show-todo"
+	ep.Examples.Parameters = map[string]map[string]endpoint.Example{
+		"name": {"task": {Summary: "A prepopulated todo", Value: "Task1"}},
 	}
+	errTodoNotFound = ep.RegisterErrorValue(http.StatusNotFound, "todo not found")
 	return ep
 }
 
 func (s Server) AddTodoEndpoint() *endpoint.Endpoint {
 	ep := &endpoint.Endpoint{}
 	ep.Name = "add-todo"
-	ep.RequestBody = Todo{}
+	ep.RequestBody = TodoRequest{}
+	ep.DecodeBody = true
+	ep.Idempotency = &endpoint.Idempotency{Store: endpoint.NewMemoryStore(24 * time.Hour)}
 	ep.Responses = map[int]interface{}{http.StatusOK: Todo{}}
-	ep.ErrorResponse = HTTPError{}
-	errCouldNotReadRequest := ep.RegisterError(http.StatusInternalServerError, "could not read request")
-	errCouldNotParseData := ep.RegisterError(http.StatusNotAcceptable, "could not parse data")
+	ep.Examples = endpoint.Examples{
+		RequestBody: map[string]endpoint.Example{
+			"shopping": {Summary: "A simple todo", Value: TodoRequest{Name: "shopping", Description: "buy milk"}},
+		},
+		Responses: map[int]map[string]endpoint.Example{
+			http.StatusOK: {"shopping": {Summary: "The todo added", Value: Todo{Name: "shopping", Description: "buy milk", Notes: []Note{}}}},
+		},
+	}
 	errAlreadyExists := ep.RegisterError(http.StatusConflict, "todo already exists")
-	ep.Auth = s.auth
 	ep.Handler = func(w http.ResponseWriter, r *http.Request) {
-		todo := &TodoRequest{}
-		b, err := io.ReadAll(r.Body)
-		if err != nil {
-			errCouldNotReadRequest(w, r)
-			return
-		}
-		err = json.Unmarshal(b, &todo)
-		if err != nil {
-			errCouldNotParseData(w, r)
-			return
-		}
+		todo := endpoint.Body(r).(TodoRequest)
 		if _, found := s.todos[todo.Name]; found {
 			errAlreadyExists(w, r)
 			return
 		}
-		s.todos.Add(todo.AsTodo())
-		respond.Auto(w, r, http.StatusOK, todo)
+		added := todo.AsTodo()
+		s.todos.Add(added)
+		respond.Auto(w, r, http.StatusOK, added)
 	}
 	return ep
 }
@@ -80,22 +82,22 @@ func (s Server) FinishTodoEndpoint() *endpoint.Endpoint {
 	ep := &endpoint.Endpoint{}
 	ep.Name = "finish-todo"
 	ep.Responses = map[int]interface{}{http.StatusOK: Todo{}}
-	ep.ErrorResponse = HTTPError{}
-	errTodoNotProvided := ep.RegisterError(http.StatusNotAcceptable, "todo not provided")
-	errTodoNotFound := ep.RegisterError(http.StatusNotFound, "todo not found")
-	ep.Auth = s.auth
-	ep.Handler = func(w http.ResponseWriter, r *http.Request) {
+	ep.Examples.Parameters = map[string]map[string]endpoint.Example{
+		"name": {"task": {Summary: "A prepopulated todo", Value: "Task1"}},
+	}
+	errTodoNotProvided := ep.RegisterErrorValue(http.StatusNotAcceptable, "todo not provided")
+	errTodoNotFound := ep.RegisterErrorValue(http.StatusNotFound, "todo not found")
+	ep.ErrHandler = func(w http.ResponseWriter, r *http.Request) error {
 		name, ok := ep.GetParamAsString("name", r)
 		if !ok || len(name) < 1 {
-			errTodoNotProvided(w, r)
-			return
+			return errTodoNotProvided
 		}
-		if _, found := s.todos[name]; found {
-			s.todos[name].Finish()
-			respond.Auto(w, r, http.StatusOK, s.todos[name])
-			return
+		todo, found := s.todos[name]
+		if !found {
+			return errTodoNotFound
 		}
-		errTodoNotFound(w, r)
+		todo.Finish()
+		return respond.Auto(w, r, http.StatusOK, todo)
 	}
 	return ep
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/unprofession-al/httpthings/endpoint"
+	"github.com/unprofession-al/httpthings/endpointtest"
+)
+
+func TestContract(t *testing.T) {
+	s, err := NewServer("127.0.0.1:8765", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	h := endpointtest.Harness{
+		Prepare: func(r *http.Request) { r.SetBasicAuth("hello", "world") },
+		Router: func() (http.Handler, endpoint.Router) {
+			r := http.NewServeMux()
+			return r, endpoint.ServeMuxRouter(r)
+		},
+	}
+	report := h.Test(t, s.routes)
+	if len(report.Skipped) > 0 {
+		t.Errorf("all endpoints are expected to be checked:\n%s", report)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
 	"github.com/rs/cors"
 	"github.com/unprofession-al/httpthings/endpoint"
@@ -17,6 +16,7 @@ type Server struct {
 	handler  http.Handler
 	todos    TodoSet
 	spec     openapi.Doc
+	routes   endpoint.Endpoints
 	auth     *endpoint.Auth
 }
 
@@ -34,21 +34,30 @@ func NewServer(listener, static string) (Server, error) {
 	}
 
 	endpoints := &endpoint.Endpoints{}
-	endpoints.Add("/api/v1/todos/", http.MethodGet, s.ListTodoEndpoint())
-	endpoints.Add("/api/v1/todos/", http.MethodPost, s.AddTodoEndpoint())
-	endpoints.Add("/api/v1/todos/{name | Name of the todo}/", http.MethodGet, s.ShowTodoEndpoint())
-	endpoints.Add("/api/v1/todos/{name | Name of the todo}/", http.MethodPut, s.FinishTodoEndpoint())
+	todos := endpoints.Group("/api/v1/todos",
+		endpoint.WithTag("todos", "Manage the todo list"),
+		endpoint.WithAuth(s.auth),
+		endpoint.WithErrorResponse(endpoint.ProblemDetails{}),
+	)
+	todos.Add("/", http.MethodGet, s.ListTodoEndpoint())
+	todos.Add("/", http.MethodPost, s.AddTodoEndpoint())
+	todos.Add("/{name | Name of the todo}/", http.MethodGet, s.ShowTodoEndpoint())
+	todos.Add("/{name | Name of the todo}/", http.MethodPut, s.FinishTodoEndpoint())
 
-	r := mux.NewRouter()
-	endpoints.PopulateRouter(r)
+	r := http.NewServeMux()
+	if err := endpoints.Populate(endpoint.ServeMuxRouter(r), endpoint.Config{}); err != nil {
+		return s, err
+	}
+	s.routes = *endpoints
 	s.spec = openapi.FromEndpoints(*endpoints)
 	s.spec.OpenAPI = "3.0.3"
 	s.spec.Info.Version = "v1"
 	s.spec.Info.Title = "Todo API"
 	s.spec.Servers = []openapi.Server{{URL: fmt.Sprintf("http://%s", listener)}}
-	r.Path("/openapi.json").HandlerFunc(s.spec.HandleHTTP)
-	r.Path("/openapi.yaml").HandlerFunc(s.spec.HandleHTTP)
-	s.handler = alice.New(cors.Default().Handler).Then(r)
+	r.HandleFunc("GET /openapi.json", s.spec.HandleHTTP)
+	r.HandleFunc("GET /openapi.yaml", s.spec.HandleHTTP)
+	r.Handle("GET /_routes", endpoints.RoutesHandler(s.auth, endpoint.Config{}))
+	s.handler = alice.New(cors.Default().Handler, endpoint.Track, logRequests).Then(r)
 	return s, nil
 }
 
@@ -58,3 +67,14 @@ func (s *Server) run() {
 		fmt.Println(err)
 	}
 }
+
+// logRequests logs the operation serving each request using the path template
+// rather than the path of the request.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		if e, caller, ok := endpoint.FromContext(r.Context()); ok {
+			fmt.Printf("INFO: %s %s served by '%s'\n", caller.Method, caller.Path, e.Name)
+		}
+	})
+}
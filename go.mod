@@ -1,10 +1,12 @@
 module github.com/unprofession-al/httpthings
 
-go 1.18
+go 1.22
 
 require (
 	github.com/apex/gateway v1.1.2
+	github.com/go-chi/chi/v5 v5.0.12
 	github.com/gorilla/mux v1.8.0
+	github.com/iancoleman/orderedmap v0.2.0
 	github.com/invopop/jsonschema v0.6.0
 	github.com/invopop/yaml v0.2.0
 	github.com/justinas/alice v1.2.0
@@ -13,7 +15,6 @@ require (
 
 require (
 	github.com/aws/aws-lambda-go v1.34.1 // indirect
-	github.com/iancoleman/orderedmap v0.2.0 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
@@ -4,52 +4,70 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/invopop/jsonschema"
 	"github.com/unprofession-al/httpthings/endpoint"
 )
 
 // FromEndpoints takes [github.com/unprofession-al/httpthings/endpoint.Endpoints] and
-// generated a [Doc] describing these endpoints.
+// generated a [Doc] describing these endpoints. It is a shorthand for [FromConfig]
+// using the zero value of [github.com/unprofession-al/httpthings/endpoint.Config].
 func FromEndpoints(groups ...endpoint.Endpoints) Doc {
+	return FromConfig(endpoint.Config{}, groups...)
+}
+
+// FromConfig takes [github.com/unprofession-al/httpthings/endpoint.Endpoints] and
+// generated a [Doc] describing these endpoints as they are served when populated
+// using the [github.com/unprofession-al/httpthings/endpoint.Config] provided.
+func FromConfig(cfg endpoint.Config, groups ...endpoint.Endpoints) Doc {
 	spec := Doc{
 		Paths: Paths{},
 	}
 	schemas := []*jsonschema.Schema{}
 	secSchemes := map[string]SecurityScheme{}
 	for _, group := range groups {
-		for caller, endpoint := range group {
-			if endpoint.Hidden {
-				continue
-			}
-			path, ok := spec.Paths[caller.Path]
-			if !ok {
-				path = PathItem{}
-			}
-			o, epSchemas, secScheme := newOperation(endpoint, endpoint.Tags...)
-			switch strings.ToUpper(caller.Method) {
-			case http.MethodGet:
-				path.Get = o
-			case http.MethodPut:
-				path.Put = o
-			case http.MethodPost:
-				path.Post = o
-			case http.MethodDelete:
-				path.Delete = o
-			case http.MethodOptions:
-				path.Options = o
-			case http.MethodHead:
-				path.Head = o
-			case http.MethodPatch:
-				path.Patch = o
-			case http.MethodTrace:
-				path.Trace = o
-			}
-			spec.Paths[caller.Path] = path
-			schemas = append(schemas, epSchemas...)
-			for k, v := range secScheme {
-				secSchemes[k] = v
+		for _, tag := range group.Tags() {
+			spec.Tags = appendTag(spec.Tags, Tag{Name: tag.Name, Description: tag.Description})
+		}
+		// endpoints answered automatically such as HEAD and OPTIONS are
+		// documented along with the endpoints of the group
+		for _, set := range []endpoint.Endpoints{group, group.Automatic(cfg)} {
+			for caller, endpoint := range set {
+				if endpoint.Hidden {
+					continue
+				}
+				docPath := strings.ReplaceAll(caller.Path, "...}", "}")
+				path, ok := spec.Paths[docPath]
+				if !ok {
+					path = PathItem{}
+				}
+				o, epSchemas, secScheme := newOperation(caller.Method, endpoint, endpoint.Tags...)
+				switch strings.ToUpper(caller.Method) {
+				case http.MethodGet:
+					path.Get = o
+				case http.MethodPut:
+					path.Put = o
+				case http.MethodPost:
+					path.Post = o
+				case http.MethodDelete:
+					path.Delete = o
+				case http.MethodOptions:
+					path.Options = o
+				case http.MethodHead:
+					path.Head = o
+				case http.MethodPatch:
+					path.Patch = o
+				case http.MethodTrace:
+					path.Trace = o
+				}
+				spec.Paths[docPath] = path
+				schemas = append(schemas, epSchemas...)
+				for k, v := range secScheme {
+					secSchemes[k] = v
+				}
 			}
 		}
 	}
@@ -67,20 +85,69 @@ func FromEndpoints(groups ...endpoint.Endpoints) Doc {
 	return spec
 }
 
-func newOperation(e *endpoint.Endpoint, tags ...string) (*Operation, []*jsonschema.Schema, SecuritySchemes) {
+// FromEndpointsByVersion generates a [Doc] for each version of the
+// [github.com/unprofession-al/httpthings/endpoint.Endpoints] provided. It is a
+// shorthand for [FromConfigByVersion] using the zero value of
+// [github.com/unprofession-al/httpthings/endpoint.Config].
+func FromEndpointsByVersion(groups ...endpoint.Endpoints) Docs {
+	return FromConfigByVersion(endpoint.Config{}, groups...)
+}
+
+// FromConfigByVersion generates a [Doc] for each version of the
+// [github.com/unprofession-al/httpthings/endpoint.Endpoints] provided, see
+// [FromConfig]. Each [Doc] describes the endpoints of its version along with all
+// endpoints which are not versioned, its Info.Version is set to the version.
+func FromConfigByVersion(cfg endpoint.Config, groups ...endpoint.Endpoints) Docs {
+	out := Docs{}
+	for _, group := range groups {
+		for _, version := range group.Versions() {
+			out[version] = Doc{}
+		}
+	}
+	for version := range out {
+		versioned := make([]endpoint.Endpoints, len(groups))
+		for i, group := range groups {
+			versioned[i] = group.Version(version)
+		}
+		doc := FromConfig(cfg, versioned...)
+		doc.Info.Version = version
+		out[version] = doc
+	}
+	return out
+}
+
+func appendTag(tags []Tag, tag Tag) []Tag {
+	for _, t := range tags {
+		if t.Name == tag.Name {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+func newOperation(method string, e *endpoint.Endpoint, tags ...string) (*Operation, []*jsonschema.Schema, SecuritySchemes) {
 	params := []Parameter{}
 	for _, p := range e.Parameters {
-		param := Parameter{
-			Name:        p.Name,
-			In:          p.Location.String(),
-			Description: p.Description,
-			Required:    true,
-			Schema:      Schema{Type: p.Type},
-		}
-		params = append(params, param)
+		params = append(params, newParameter(p, e.Examples.Parameters[p.Name]))
+	}
+	body, bSchema := newRequest(e.RequestBody, e.Examples.RequestBody)
+	if body != nil && e.DecodeBody {
+		body.Required = true
+	}
+	responses, rSchemas := newResponses(documentedResponses(method, e), e.Examples.Responses)
+	params, responses = conditional(method, e, params, responses)
+	responses = paginated(e, responses)
+	if e.Idempotency != nil {
+		params = append(params, Parameter{
+			Name: "Idempotency-Key",
+			In:   "header",
+			Description: "Unique key of the request which makes retries safe. The first response " +
+				"for a key is replayed for retries using the same key and body, replayed responses " +
+				"carry the 'Idempotent-Replayed' header.",
+			Required: e.Idempotency.Required,
+			Schema:   Schema{Type: "string"},
+		})
 	}
-	body, bSchema := newRequest(e.RequestBody)
-	responses, rSchemas := newResponses(e.Responses)
 	out := &Operation{
 		Summary:     e.Name,
 		Description: e.Description,
@@ -89,6 +156,8 @@ func newOperation(e *endpoint.Endpoint, tags ...string) (*Operation, []*jsonsche
 		RequestBody: body,
 		Parameters:  params,
 		Tags:        tags,
+		Deprecated:  e.Lifecycle.Deprecated,
+		Extensions:  extensions(e),
 	}
 	schemas := append(rSchemas, bSchema)
 	sec := map[string]SecurityScheme{}
@@ -101,6 +170,137 @@ func newOperation(e *endpoint.Endpoint, tags ...string) (*Operation, []*jsonsche
 	return out, schemas, sec
 }
 
+// extensions returns the specification extensions of an endpoint.
+func extensions(e *endpoint.Endpoint) map[string]interface{} {
+	out := lifecycleExtensions(e.Lifecycle)
+	if e.RateLimit != nil {
+		if out == nil {
+			out = map[string]interface{}{}
+		}
+		out["x-ratelimit"] = map[string]interface{}{
+			"requests": e.RateLimit.Requests,
+			"window":   e.RateLimit.Window.String(),
+			"policy":   e.RateLimit.Policy(),
+		}
+	}
+	return out
+}
+
+// lifecycleExtensions returns the specification extensions describing the
+// lifecycle of an endpoint which has no representation in the specification.
+func lifecycleExtensions(l endpoint.Lifecycle) map[string]interface{} {
+	out := map[string]interface{}{}
+	if l.Stability != "" {
+		out["x-stability"] = l.Stability
+	}
+	if !l.Sunset.IsZero() {
+		out["x-sunset"] = l.Sunset.UTC().Format(time.RFC3339)
+	}
+	if l.Successor != "" {
+		out["x-successor"] = l.Successor
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func newParameter(p endpoint.Parameter, examples map[string]endpoint.Example) Parameter {
+	schema := Schema{
+		Type:    p.Type,
+		Format:  p.Format,
+		Minimum: p.Minimum,
+		Maximum: p.Maximum,
+		Pattern: p.Pattern,
+	}
+	for _, e := range p.Enum {
+		schema.Enum = append(schema.Enum, typedValue(p.Type, p.Items, e))
+	}
+	if p.Default != "" {
+		schema.Default = typedValue(p.Type, p.Items, p.Default)
+	}
+	out := Parameter{
+		Name:        p.Name,
+		In:          p.Location.String(),
+		Description: p.Description,
+		Required:    p.Required || p.Location == endpoint.ParameterLocationPath,
+		Deprecated:  p.Deprecated,
+		Schema:      schema,
+	}
+	if p.Type == endpoint.ParameterTypeArray {
+		items := schema
+		items.Type, items.Default = p.Items, nil
+		out.Schema = Schema{Type: p.Type, Items: &items}
+		if schema.Default != nil {
+			out.Schema.Default = []interface{}{schema.Default}
+		}
+		style := p.EffectiveStyle()
+		explode := style == endpoint.ParameterStyleForm
+		out.Style, out.Explode = string(style), &explode
+	}
+	out.Examples = newExamples(examples, func(v interface{}) interface{} {
+		val, _ := v.(string)
+		if p.Type != endpoint.ParameterTypeArray {
+			return typedValue(p.Type, p.Items, val)
+		}
+		sep := map[endpoint.ParameterStyle]string{
+			endpoint.ParameterStyleSimple:         ",",
+			endpoint.ParameterStyleSpaceDelimited: " ",
+			endpoint.ParameterStylePipeDelimited:  "|",
+		}[p.EffectiveStyle()]
+		items := []string{val}
+		if sep != "" {
+			items = strings.Split(val, sep)
+		}
+		vals := []interface{}{}
+		for _, item := range items {
+			vals = append(vals, typedValue(p.Type, p.Items, item))
+		}
+		return vals
+	})
+	return out
+}
+
+// newExamples converts the examples of an endpoint, the values are passed
+// through convert if provided.
+func newExamples(in map[string]endpoint.Example, convert func(interface{}) interface{}) map[string]Example {
+	if len(in) == 0 {
+		return nil
+	}
+	out := map[string]Example{}
+	for name, example := range in {
+		value := example.Value
+		if convert != nil {
+			value = convert(value)
+		}
+		out[name] = Example{Summary: example.Summary, Description: example.Description, Value: value}
+	}
+	return out
+}
+
+// typedValue converts the string representation of a parameter value into the
+// type of the parameter so that it is rendered properly in the document.
+func typedValue(typ, items, val string) interface{} {
+	if typ == endpoint.ParameterTypeArray {
+		typ = items
+	}
+	switch typ {
+	case endpoint.ParameterTypeInteger:
+		if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return i
+		}
+	case endpoint.ParameterTypeNumber:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	case endpoint.ParameterTypeBoolean:
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return val
+}
+
 func newSchema(t string, v interface{}) Schema {
 	array := reflect.TypeOf(v).Kind() == reflect.Slice
 	out := &Schema{}
@@ -124,29 +324,138 @@ func newSchema(t string, v interface{}) Schema {
 	return *out
 }
 
-func newResponses(in map[int]interface{}) (Responses, []*jsonschema.Schema) {
+// documentedResponses returns the responses of the endpoint including those
+// which are not written by the handler itself but by the mechanisms enabled
+// on the endpoint.
+func documentedResponses(method string, e *endpoint.Endpoint) map[int]interface{} {
+	out := map[int]interface{}{}
+	for code, data := range e.Responses {
+		out[code] = data
+	}
+	if len(out) == 0 {
+		out[http.StatusOK] = ""
+	}
+	var errResp interface{} = ""
+	if e.ErrorResponse != nil {
+		errResp = e.ErrorResponse
+	}
+	addMissing := func(codes ...int) {
+		for _, code := range codes {
+			if _, ok := out[code]; !ok {
+				out[code] = errResp
+			}
+		}
+	}
+	if e.DecodeBody && e.RequestBody != nil {
+		addMissing(http.StatusBadRequest, http.StatusUnprocessableEntity)
+	}
+	if e.Lifecycle.RejectAfterSunset && !e.Lifecycle.Sunset.IsZero() {
+		addMissing(http.StatusGone)
+	}
+	if e.RateLimit != nil {
+		addMissing(http.StatusTooManyRequests)
+	}
+	if e.MaxBodyBytes > 0 {
+		addMissing(http.StatusRequestEntityTooLarge)
+	}
+	if e.MaxInFlight > 0 {
+		addMissing(http.StatusServiceUnavailable)
+	}
+	if e.Timeout > 0 {
+		addMissing(http.StatusGatewayTimeout)
+	}
+	if e.Idempotency != nil {
+		addMissing(http.StatusConflict, http.StatusUnprocessableEntity)
+		if e.Idempotency.Required {
+			addMissing(http.StatusBadRequest)
+		}
+	}
+	if e.Preconditions != nil && e.Preconditions.RequiresMatch(method) {
+		addMissing(http.StatusPreconditionFailed, http.StatusPreconditionRequired)
+	}
+	return out
+}
+
+// conditional documents the headers and responses of endpoints with
+// [github.com/unprofession-al/httpthings/endpoint.Preconditions].
+func conditional(method string, e *endpoint.Endpoint, params []Parameter, responses Responses) ([]Parameter, Responses) {
+	if e.Preconditions == nil {
+		return params, responses
+	}
+	header := func(name, description string, required bool) Parameter {
+		return Parameter{Name: name, In: "header", Description: description, Required: required, Schema: Schema{Type: "string"}}
+	}
+	switch {
+	case e.Preconditions.RequiresMatch(method):
+		params = append(params, header("If-Match", "Entity tag of the resource to be modified", true))
+	case method == http.MethodGet || method == http.MethodHead:
+		params = append(params, header("If-None-Match", "Entity tags of the representations known to the client", false))
+		for code, resp := range responses {
+			if strings.HasPrefix(code, "2") {
+				resp.Headers = map[string]Header{"ETag": {Description: "Entity tag of the representation", Schema: Schema{Type: "string"}}}
+				responses[code] = resp
+			}
+		}
+		responses[fmt.Sprint(http.StatusNotModified)] = Response{Description: statusText(http.StatusNotModified)}
+	}
+	return params, responses
+}
+
+// paginated wraps the schema of the success response of endpoints with
+// [github.com/unprofession-al/httpthings/endpoint.Pagination] in the envelope
+// of the page and documents the 'Link' header.
+func paginated(e *endpoint.Endpoint, responses Responses) Responses {
+	code := fmt.Sprint(http.StatusOK)
+	resp, ok := responses[code]
+	if e.Pagination == nil || !ok {
+		return responses
+	}
+	items, next, prev, total := e.Pagination.Envelope.FieldNames()
+	content := Content{}
+	for mt, def := range resp.Content {
+		def.Schema = Schema{
+			Type: "object",
+			Properties: map[string]Schema{
+				items: def.Schema,
+				next:  {Type: "string", Format: "uri-reference"},
+				prev:  {Type: "string", Format: "uri-reference"},
+				total: {Type: "integer"},
+			},
+		}
+		content[mt] = def
+	}
+	resp.Content = content
+	if resp.Headers == nil {
+		resp.Headers = map[string]Header{}
+	}
+	resp.Headers["Link"] = Header{Description: "Links to the next and previous page according to RFC 8288", Schema: Schema{Type: "string"}}
+	responses[code] = resp
+	return responses
+}
+
+func newResponses(in map[int]interface{}, examples map[int]map[string]endpoint.Example) (Responses, []*jsonschema.Schema) {
 	out := Responses{}
 	schemas := []*jsonschema.Schema{}
 
 	if len(in) == 0 {
 		code := http.StatusOK
-		resp, schema := newResponse(code, "")
+		resp, schema := newResponse(code, "", nil)
 		schemas = append(schemas, schema)
 		out[fmt.Sprint(code)] = *resp
 	}
 	for code, data := range in {
-		resp, schema := newResponse(code, data)
+		resp, schema := newResponse(code, data, examples[code])
 		schemas = append(schemas, schema)
 		out[fmt.Sprint(code)] = *resp
 	}
 	return out, schemas
 }
 
-func newResponse(code int, in interface{}) (*Response, *jsonschema.Schema) {
+func newResponse(code int, in interface{}, examples map[string]endpoint.Example) (*Response, *jsonschema.Schema) {
 	if in == nil {
 		return nil, nil
 	}
-	schema := jsonschema.Reflect(in)
+	schema := endpoint.ReflectSchema(in)
 	nameTokens := strings.SplitN(reflect.TypeOf(in).String(), ".", 2)
 	var reference string
 	if len(nameTokens) < 2 {
@@ -157,19 +466,20 @@ func newResponse(code int, in interface{}) (*Response, *jsonschema.Schema) {
 	resp := &Response{
 		Description: statusText(code),
 		Content: Content{
-			"application/json": {
-				Schema: newSchema(reference, in),
+			mediaType(in): {
+				Schema:   newSchema(reference, in),
+				Examples: newExamples(examples, nil),
 			},
 		},
 	}
 	return resp, schema
 }
 
-func newRequest(in interface{}) (*Request, *jsonschema.Schema) {
+func newRequest(in interface{}, examples map[string]endpoint.Example) (*Request, *jsonschema.Schema) {
 	if in == nil {
 		return nil, nil
 	}
-	schema := jsonschema.Reflect(in)
+	schema := endpoint.ReflectSchema(in)
 	nameTokens := strings.SplitN(reflect.TypeOf(in).String(), ".", 2)
 	var reference string
 	if len(nameTokens) < 2 {
@@ -179,10 +489,18 @@ func newRequest(in interface{}) (*Request, *jsonschema.Schema) {
 	}
 	req := &Request{
 		Content: Content{
-			"application/json": {
-				Schema: newSchema(reference, in),
+			mediaType(in): {
+				Schema:   newSchema(reference, in),
+				Examples: newExamples(examples, nil),
 			},
 		},
 	}
 	return req, schema
 }
+
+func mediaType(in interface{}) string {
+	if mt, ok := in.(endpoint.MediaTyper); ok {
+		return mt.MediaType()
+	}
+	return "application/json"
+}
@@ -0,0 +1,749 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unprofession-al/httpthings/endpoint"
+)
+
+type todo struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+}
+
+func TestResponseMediaTypes(t *testing.T) {
+	cases := map[string]struct {
+		errorResponse endpoint.ErrorResponse
+		requestBody   interface{}
+		expected      map[string][]string
+	}{
+		"Problem details": {
+			errorResponse: endpoint.ProblemDetails{},
+			expected: map[string][]string{
+				"200": {"application/json"},
+				"404": {"application/problem+json"},
+			},
+		},
+		"Details without error response": {
+			expected: map[string][]string{
+				"200": {"application/json"},
+				"404": {"application/json"},
+			},
+		},
+		"Request body with media type": {
+			requestBody: endpoint.ProblemDetails{},
+			expected: map[string][]string{
+				"200":         {"application/json"},
+				"404":         {"application/json"},
+				"requestBody": {"application/problem+json"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{
+				Responses:     map[int]interface{}{http.StatusOK: todo{}},
+				ErrorResponse: tc.errorResponse,
+				RequestBody:   tc.requestBody,
+			}
+			e.RegisterError(http.StatusNotFound, "todo not found")
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodPost, e)), "/todos/", http.MethodPost)
+			have := map[string][]string{}
+			for status, res := range op.Responses {
+				have[status] = mediaTypes(res.Content)
+			}
+			if op.RequestBody != nil {
+				have["requestBody"] = mediaTypes(op.RequestBody.Content)
+			}
+			if len(have) != len(tc.expected) {
+				t.Fatalf("media types are not as expected, have %v, need %v", have, tc.expected)
+			}
+			for key, expected := range tc.expected {
+				if !equal(have[key], expected) {
+					t.Errorf("media types of '%s' are not as expected, have %v, need %v", key, have[key], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParameterSchemas(t *testing.T) {
+	lower, upper := 1.0, 10.0
+	cases := map[string]struct {
+		param    endpoint.Parameter
+		expected string
+	}{
+		"Path parameter is required": {
+			param:    endpoint.Parameter{Name: "id", Location: endpoint.ParameterLocationPath, Type: endpoint.ParameterTypeString, Format: "uuid"},
+			expected: `{"name":"id","in":"path","description":"","required":true,"deprecated":false,"allowEmptyValue":false,"schema":{"type":"string","format":"uuid"}}`,
+		},
+		"Integer with bounds and default": {
+			param:    endpoint.Parameter{Name: "limit", Type: endpoint.ParameterTypeInteger, Minimum: &lower, Maximum: &upper, Default: "5"},
+			expected: `{"name":"limit","in":"query","description":"","required":false,"deprecated":false,"allowEmptyValue":false,"schema":{"type":"integer","minimum":1,"maximum":10,"default":5}}`,
+		},
+		"String with enum and pattern": {
+			param:    endpoint.Parameter{Name: "state", Type: endpoint.ParameterTypeString, Enum: []string{"open", "done"}, Pattern: "^[a-z]+$", Deprecated: true},
+			expected: `{"name":"state","in":"query","description":"","required":false,"deprecated":true,"allowEmptyValue":false,"schema":{"type":"string","enum":["open","done"],"pattern":"^[a-z]+$"}}`,
+		},
+		"Array in form style": {
+			param:    endpoint.Parameter{Name: "id", Type: endpoint.ParameterTypeArray, Items: endpoint.ParameterTypeInteger, Enum: []string{"1", "2"}},
+			expected: `{"name":"id","in":"query","description":"","required":false,"deprecated":false,"allowEmptyValue":false,"style":"form","explode":true,"schema":{"type":"array","items":{"type":"integer","enum":[1,2]}}}`,
+		},
+		"Array in pipe delimited style with default": {
+			param:    endpoint.Parameter{Name: "id", Type: endpoint.ParameterTypeArray, Items: endpoint.ParameterTypeBoolean, Style: endpoint.ParameterStylePipeDelimited, Default: "true"},
+			expected: `{"name":"id","in":"query","description":"","required":false,"deprecated":false,"allowEmptyValue":false,"style":"pipeDelimited","explode":false,"schema":{"type":"array","items":{"type":"boolean"},"default":[true]}}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Parameters: []endpoint.Parameter{tc.param}}
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodGet, e)), "/todos/", http.MethodGet)
+			if len(op.Parameters) != 1 {
+				t.Fatalf("expected one parameter, have %d", len(op.Parameters))
+			}
+			have, err := json.Marshal(op.Parameters[0])
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if string(have) != tc.expected {
+				t.Errorf("parameter is not as expected, have %s, need %s", have, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGroupTags(t *testing.T) {
+	endpoints := endpoint.Endpoints{}
+	todos := endpoints.Group("/todos", endpoint.WithTag("todos", "Manage todos"))
+	if err := todos.Add("/", http.MethodGet, &endpoint.Endpoint{Tags: []string{"list"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	other := endpoint.Endpoints{}
+	if err := other.Group("/", endpoint.WithTag("todos", "Duplicate")).Add("/done", http.MethodGet, &endpoint.Endpoint{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	doc := FromEndpoints(endpoints, other)
+	if len(doc.Tags) != 1 || doc.Tags[0].Name != "todos" || doc.Tags[0].Description != "Manage todos" {
+		t.Errorf("tags are not as expected, have %v", doc.Tags)
+	}
+	op := operationOf(t, doc, "/todos/", http.MethodGet)
+	if !equal(op.Tags, []string{"todos", "list"}) {
+		t.Errorf("tags of the operation are not as expected, have %v", op.Tags)
+	}
+}
+
+func TestAutomaticMethods(t *testing.T) {
+	cases := map[string]struct {
+		cfg      endpoint.Config
+		expected []string
+	}{
+		"Head and options documented": {
+			expected: []string{http.MethodGet, http.MethodHead, http.MethodOptions},
+		},
+		"Automatic methods disabled": {
+			cfg:      endpoint.Config{DisableAutoMethods: true},
+			expected: []string{http.MethodGet},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Description: "List todos", Responses: map[int]interface{}{http.StatusOK: []todo{}}}
+			doc := FromConfig(tc.cfg, endpointsWith(t, "/todos/", http.MethodGet, e))
+			item := doc.Paths["/todos/"]
+			have := []string{}
+			for method, op := range map[string]*Operation{http.MethodGet: item.Get, http.MethodHead: item.Head, http.MethodOptions: item.Options} {
+				if op != nil {
+					have = append(have, method)
+				}
+			}
+			sort.Strings(have)
+			if !equal(have, tc.expected) {
+				t.Fatalf("operations are not as expected, have %v, need %v", have, tc.expected)
+			}
+			if item.Head != nil && item.Head.Description != "List todos (headers only)" {
+				t.Errorf("head operation is not as expected, have %q", item.Head.Description)
+			}
+			if item.Options != nil {
+				if _, ok := item.Options.Responses["204"]; !ok {
+					t.Errorf("options operation is expected to document '204 No Content', have %v", item.Options.Responses)
+				}
+			}
+		})
+	}
+}
+
+func TestPathTemplates(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		docPath  string
+		expected string
+	}{
+		"Type constraint": {
+			path:     "/todos/{id:int | Todo id}/",
+			docPath:  "/todos/{id}/",
+			expected: `{"name":"id","in":"path","description":"Todo id","required":true,"deprecated":false,"allowEmptyValue":false,"schema":{"type":"integer"}}`,
+		},
+		"Regular expression constraint": {
+			path:     "/todos/{code:[A-Z]{3}}/",
+			docPath:  "/todos/{code}/",
+			expected: `{"name":"code","in":"path","description":"code","required":true,"deprecated":false,"allowEmptyValue":false,"schema":{"type":"string","pattern":"^[A-Z]{3}$"}}`,
+		},
+		"Catch all parameter": {
+			path:     "/files/{path... | File path}",
+			docPath:  "/files/{path}",
+			expected: `{"name":"path","in":"path","description":"File path","required":true,"deprecated":false,"allowEmptyValue":false,"schema":{"type":"string"}}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			op := operationOf(t, FromEndpoints(endpointsWith(t, tc.path, http.MethodGet, &endpoint.Endpoint{})), tc.docPath, http.MethodGet)
+			if len(op.Parameters) != 1 {
+				t.Fatalf("expected one parameter, have %d", len(op.Parameters))
+			}
+			have, err := json.Marshal(op.Parameters[0])
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if string(have) != tc.expected {
+				t.Errorf("parameter is not as expected, have %s, need %s", have, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTrailingSlash(t *testing.T) {
+	endpoints := endpoint.Endpoints{}
+	g := endpoints.Group("/todos", endpoint.WithTrailingSlash(endpoint.TrailingSlashStrip))
+	if err := g.Add("/{name}/", http.MethodGet, &endpoint.Endpoint{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	doc := FromEndpoints(endpoints)
+	operationOf(t, doc, "/todos/{name}", http.MethodGet)
+	if _, ok := doc.Paths["/todos/{name}/"]; ok {
+		t.Errorf("path is expected to be documented in its canonical form only")
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	sunset := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		lifecycle  endpoint.Lifecycle
+		deprecated bool
+		extensions map[string]interface{}
+		gone       bool
+	}{
+		"Generally available": {
+			lifecycle:  endpoint.Lifecycle{Stability: endpoint.StabilityGA},
+			extensions: map[string]interface{}{"x-stability": "ga"},
+		},
+		"Deprecated with sunset and successor": {
+			lifecycle:  endpoint.Lifecycle{Deprecated: true, Sunset: sunset, Successor: "/api/v2/todos/"},
+			deprecated: true,
+			extensions: map[string]interface{}{"x-sunset": "2024-12-31T00:00:00Z", "x-successor": "/api/v2/todos/"},
+		},
+		"Rejected after sunset": {
+			lifecycle:  endpoint.Lifecycle{Deprecated: true, Sunset: sunset, RejectAfterSunset: true},
+			deprecated: true,
+			extensions: map[string]interface{}{"x-sunset": "2024-12-31T00:00:00Z"},
+			gone:       true,
+		},
+		"Without lifecycle": {
+			extensions: map[string]interface{}{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Lifecycle: tc.lifecycle}
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodGet, e)), "/todos/", http.MethodGet)
+			raw, err := json.Marshal(op)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			members := map[string]interface{}{}
+			if err := json.Unmarshal(raw, &members); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if members["deprecated"] != tc.deprecated {
+				t.Errorf("deprecated is not as expected, have %v, need %v", members["deprecated"], tc.deprecated)
+			}
+			for key, value := range members {
+				if strings.HasPrefix(key, "x-") && tc.extensions[key] != value {
+					t.Errorf("extension '%s' is not expected, have %v", key, value)
+				}
+			}
+			for key, value := range tc.extensions {
+				if members[key] != value {
+					t.Errorf("extension '%s' is not as expected, have %v, need %v", key, members[key], value)
+				}
+			}
+			if _, gone := op.Responses["410"]; gone != tc.gone {
+				t.Errorf("documentation of '410 Gone' is not as expected, have %v, need %v", gone, tc.gone)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cases := map[string]struct {
+		limit     *endpoint.RateLimit
+		extension map[string]interface{}
+	}{
+		"Rate limited": {
+			limit: &endpoint.RateLimit{Requests: 10, Window: time.Minute, Burst: 20},
+			extension: map[string]interface{}{
+				"requests": float64(10),
+				"window":   "1m0s",
+				"policy":   "10;w=60;burst=20",
+			},
+		},
+		"Without rate limit": {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{RateLimit: tc.limit}
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodGet, e)), "/todos/", http.MethodGet)
+			raw, err := json.Marshal(op)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			members := map[string]interface{}{}
+			if err := json.Unmarshal(raw, &members); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			extension, documented := members["x-ratelimit"].(map[string]interface{})
+			if documented != (tc.extension != nil) {
+				t.Fatalf("documentation of the rate limit is not as expected, have %v", members["x-ratelimit"])
+			}
+			for key, value := range tc.extension {
+				if extension[key] != value {
+					t.Errorf("member '%s' of the rate limit is not as expected, have %v, need %v", key, extension[key], value)
+				}
+			}
+			if _, ok := op.Responses["429"]; ok != documented {
+				t.Errorf("documentation of '429 Too Many Requests' is not as expected, have %v, need %v", ok, documented)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	cases := map[string]struct {
+		endpoint endpoint.Endpoint
+		status   string
+	}{
+		"Body size limit":   {endpoint: endpoint.Endpoint{MaxBodyBytes: 1024}, status: "413"},
+		"In-flight limit":   {endpoint: endpoint.Endpoint{MaxInFlight: 8}, status: "503"},
+		"Timeout":           {endpoint: endpoint.Endpoint{Timeout: time.Second}, status: "504"},
+		"Without any limit": {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := tc.endpoint
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodPost, &e)), "/todos/", http.MethodPost)
+			for _, status := range []string{"413", "503", "504"} {
+				if _, ok := op.Responses[status]; ok != (status == tc.status) {
+					t.Errorf("documentation of '%s' is not as expected, have %v", status, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestPreconditions(t *testing.T) {
+	cases := map[string]struct {
+		method    string
+		header    string
+		required  bool
+		responses []string
+		etag      bool
+	}{
+		"Conditional get": {
+			method:    http.MethodGet,
+			header:    "If-None-Match",
+			responses: []string{"200", "304"},
+			etag:      true,
+		},
+		"Put requiring a match": {
+			method:    http.MethodPut,
+			header:    "If-Match",
+			required:  true,
+			responses: []string{"200", "412", "428"},
+		},
+		"Unconditional post": {
+			method:    http.MethodPost,
+			responses: []string{"200"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Preconditions: &endpoint.Preconditions{
+				ETag: func(r *http.Request) (string, bool) { return `"a"`, true },
+			}}
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", tc.method, e)), "/todos/", tc.method)
+			responses := []string{}
+			for code := range op.Responses {
+				responses = append(responses, code)
+			}
+			sort.Strings(responses)
+			if !equal(responses, tc.responses) {
+				t.Errorf("responses are not as expected, have %v, need %v", responses, tc.responses)
+			}
+			headers := []Parameter{}
+			for _, p := range op.Parameters {
+				if p.In == "header" {
+					headers = append(headers, p)
+				}
+			}
+			if tc.header == "" {
+				if len(headers) > 0 {
+					t.Errorf("no header parameters expected, have %v", headers)
+				}
+			} else if len(headers) != 1 || headers[0].Name != tc.header || headers[0].Required != tc.required {
+				t.Errorf("header parameter %s is not as expected, have %v", tc.header, headers)
+			}
+			if _, ok := op.Responses["200"].Headers["ETag"]; ok != tc.etag {
+				t.Errorf("documentation of the 'ETag' header is not as expected, have %v, need %v", ok, tc.etag)
+			}
+		})
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	cases := map[string]struct {
+		required  bool
+		responses []string
+	}{
+		"Optional key": {responses: []string{"200", "409", "422"}},
+		"Required key": {required: true, responses: []string{"200", "400", "409", "422"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Idempotency: &endpoint.Idempotency{Store: endpoint.NewMemoryStore(time.Hour), Required: tc.required}}
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodPost, e)), "/todos/", http.MethodPost)
+			responses := []string{}
+			for code := range op.Responses {
+				responses = append(responses, code)
+			}
+			sort.Strings(responses)
+			if !equal(responses, tc.responses) {
+				t.Errorf("responses are not as expected, have %v, need %v", responses, tc.responses)
+			}
+			if len(op.Parameters) != 1 || op.Parameters[0].Name != "Idempotency-Key" || op.Parameters[0].Required != tc.required {
+				t.Errorf("'Idempotency-Key' header is not documented as expected, have %v", op.Parameters)
+			}
+		})
+	}
+}
+
+func TestPagination(t *testing.T) {
+	type todo struct {
+		Name string `json:"name"`
+	}
+	cases := map[string]struct {
+		envelope endpoint.Envelope
+		fields   []string
+	}{
+		"Default envelope": {
+			fields: []string{"items", "next", "prev", "total"},
+		},
+		"Custom envelope": {
+			envelope: endpoint.Envelope{Items: "todos", Next: "after", Prev: "before", Total: "count"},
+			fields:   []string{"after", "before", "count", "todos"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &endpoint.Endpoint{Responses: map[int]interface{}{http.StatusOK: []todo{}}}
+			e.Paginate(endpoint.Pagination{Envelope: tc.envelope})
+			op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodGet, e)), "/todos/", http.MethodGet)
+			resp := op.Responses["200"]
+			if _, ok := resp.Headers["Link"]; !ok {
+				t.Errorf("'Link' header is expected to be documented")
+			}
+			if len(resp.Content) == 0 {
+				t.Fatalf("content is expected to be documented")
+			}
+			for mt, def := range resp.Content {
+				fields := []string{}
+				for field := range def.Schema.Properties {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				if def.Schema.Type != "object" || !equal(fields, tc.fields) {
+					t.Errorf("envelope of %s is not as expected, have %s %v, need object %v", mt, def.Schema.Type, fields, tc.fields)
+				}
+			}
+			params := []string{}
+			for _, p := range op.Parameters {
+				params = append(params, p.Name)
+			}
+			sort.Strings(params)
+			if !equal(params, []string{"limit", "offset"}) {
+				t.Errorf("parameters are not as expected, have %v", params)
+			}
+		})
+	}
+}
+
+func TestFiltering(t *testing.T) {
+	type todo struct {
+		Name     string    `json:"name"`
+		Priority int       `json:"priority"`
+		Due      time.Time `json:"due"`
+	}
+	e := &endpoint.Endpoint{Responses: map[int]interface{}{http.StatusOK: []todo{}}}
+	err := e.Filterable(endpoint.Filtering{
+		Filter: map[string][]endpoint.FilterOperator{
+			"name":     {endpoint.OperatorEq, endpoint.OperatorIn},
+			"priority": {endpoint.OperatorGt},
+			"due":      {endpoint.OperatorLt},
+		},
+		Sort: []string{"name", "due"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodGet, e)), "/todos/", http.MethodGet)
+	params := map[string]Parameter{}
+	for _, p := range op.Parameters {
+		params[p.Name] = p
+	}
+
+	cases := map[string]struct {
+		name   string
+		typ    string
+		format string
+		items  string
+		style  string
+	}{
+		"Equal":                {name: "filter[name]", typ: "string"},
+		"One of several":       {name: "filter[name][in]", typ: "array", items: "string", style: "simple"},
+		"Greater than integer": {name: "filter[priority][gt]", typ: "integer"},
+		"Less than date-time":  {name: "filter[due][lt]", typ: "string", format: "date-time"},
+		"Sort":                 {name: "sort", typ: "string"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, ok := params[tc.name]
+			if !ok {
+				t.Fatalf("parameter '%s' is not documented", tc.name)
+			}
+			if p.In != "query" || p.Required {
+				t.Errorf("parameter is expected to be an optional query parameter, have %s, required %v", p.In, p.Required)
+			}
+			if p.Schema.Type != tc.typ || p.Schema.Format != tc.format || p.Style != tc.style {
+				t.Errorf("parameter is not as expected, have %s %s %s", p.Schema.Type, p.Schema.Format, p.Style)
+			}
+			if tc.items != "" && (p.Schema.Items == nil || p.Schema.Items.Type != tc.items) {
+				t.Errorf("items are not as expected, have %v, need %s", p.Schema.Items, tc.items)
+			}
+		})
+	}
+	if pattern := params["sort"].Schema.Pattern; pattern != "^-?(name|due)(,-?(name|due))*$" {
+		t.Errorf("pattern of the sort parameter is not as expected, have %s", pattern)
+	}
+	if len(params) != len(cases) {
+		t.Errorf("parameters are not as expected, have %v", op.Parameters)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	e := &endpoint.Endpoint{
+		RequestBody: todo{},
+		Responses:   map[int]interface{}{http.StatusOK: todo{}},
+		Parameters: []endpoint.Parameter{
+			{Name: "priority", Location: endpoint.ParameterLocationQuery, Type: endpoint.ParameterTypeInteger},
+			{Name: "done", Location: endpoint.ParameterLocationQuery, Type: endpoint.ParameterTypeBoolean},
+			{Name: "ids", Location: endpoint.ParameterLocationQuery, Type: endpoint.ParameterTypeArray, Items: endpoint.ParameterTypeInteger, Style: endpoint.ParameterStylePipeDelimited},
+			{Name: "tag", Location: endpoint.ParameterLocationQuery, Type: endpoint.ParameterTypeArray, Items: endpoint.ParameterTypeString},
+		},
+		Lifecycle: endpoint.Lifecycle{Stability: endpoint.StabilityGA},
+		Examples: endpoint.Examples{
+			RequestBody: map[string]endpoint.Example{"shopping": {Summary: "A todo", Value: todo{Name: "shopping"}}},
+			Responses:   map[int]map[string]endpoint.Example{http.StatusOK: {"done": {Value: todo{Name: "shopping", Done: true}}}},
+			Parameters: map[string]map[string]endpoint.Example{
+				"priority": {"high": {Value: "3"}},
+				"done":     {"yes": {Value: "true"}},
+				"ids":      {"some": {Value: "3|4"}},
+				"tag":      {"home": {Value: "home,garden"}},
+			},
+		},
+	}
+	op := operationOf(t, FromEndpoints(endpointsWith(t, "/todos/", http.MethodPost, e)), "/todos/", http.MethodPost)
+	// the operation is rendered via its MarshalJSON as it has extensions
+	raw, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	rendered := struct {
+		Stability  string `json:"x-stability"`
+		Parameters []struct {
+			Name     string `json:"name"`
+			Examples map[string]struct {
+				Value interface{} `json:"value"`
+			} `json:"examples"`
+		} `json:"parameters"`
+		RequestBody struct {
+			Content map[string]struct {
+				Examples map[string]struct {
+					Summary string `json:"summary"`
+					Value   todo   `json:"value"`
+				} `json:"examples"`
+			} `json:"content"`
+		} `json:"requestBody"`
+		Responses map[string]struct {
+			Content map[string]struct {
+				Examples map[string]struct {
+					Value todo `json:"value"`
+				} `json:"examples"`
+			} `json:"content"`
+		} `json:"responses"`
+	}{}
+	if err := json.Unmarshal(raw, &rendered); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if rendered.Stability != "ga" {
+		t.Errorf("extensions are expected to be rendered along with the examples, have %s", raw)
+	}
+
+	cases := map[string]struct {
+		example string
+		value   string
+	}{
+		"Integer parameter":                    {example: "high", value: `3`},
+		"Boolean parameter":                    {example: "yes", value: `true`},
+		"Pipe delimited array parameter":       {example: "some", value: `[3,4]`},
+		"Array parameter using the form style": {example: "home", value: `["home,garden"]`},
+	}
+	params := map[string]string{}
+	for _, p := range rendered.Parameters {
+		for name, example := range p.Examples {
+			value, _ := json.Marshal(example.Value)
+			params[name] = string(value)
+		}
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if params[tc.example] != tc.value {
+				t.Errorf("example value is not as expected, have %s, need %s", params[tc.example], tc.value)
+			}
+		})
+	}
+	if len(rendered.RequestBody.Content) == 0 || len(rendered.Responses["200"].Content) == 0 {
+		t.Fatalf("request body and response are expected to be documented, have %s", raw)
+	}
+	for mt, def := range rendered.RequestBody.Content {
+		if example := def.Examples["shopping"]; example.Summary != "A todo" || example.Value.Name != "shopping" {
+			t.Errorf("request body example of %s is not as expected, have %v", mt, example)
+		}
+	}
+	for mt, def := range rendered.Responses["200"].Content {
+		if example := def.Examples["done"]; !example.Value.Done {
+			t.Errorf("response example of %s is not as expected, have %v", mt, example)
+		}
+	}
+}
+
+func TestVersionedDocs(t *testing.T) {
+	endpoints := endpoint.Endpoints{}
+	endpoints.AddVersioned("/api/{version}/todos/", http.MethodGet, &endpoint.Endpoint{Name: "list"}, "v1", "v2")
+	endpoints.AddVersioned("/todos/", http.MethodDelete, &endpoint.Endpoint{Name: "delete"}, "v2")
+	endpoints.Add("/health/", http.MethodGet, &endpoint.Endpoint{Name: "health"})
+
+	docs := FromEndpointsByVersion(endpoints)
+	if len(docs) != 2 {
+		t.Fatalf("a document per version is expected, have %d", len(docs))
+	}
+	for _, version := range []string{"v1", "v2"} {
+		doc := docs[version]
+		if doc.Info.Version != version {
+			t.Errorf("version of the document is not as expected, have %q, need %q", doc.Info.Version, version)
+		}
+		operationOf(t, doc, "/api/"+version+"/todos/", http.MethodGet)
+		operationOf(t, doc, "/health/", http.MethodGet)
+	}
+	if _, ok := docs["v1"].Paths["/todos/"]; ok {
+		t.Errorf("endpoints of other versions are not expected to be documented")
+	}
+	operationOf(t, docs["v2"], "/todos/", http.MethodDelete)
+
+	cases := map[string]struct {
+		path   string
+		status int
+		marker string
+	}{
+		"Document as JSON": {path: "/openapi/v1.json", status: http.StatusOK, marker: `"version": "v1"`},
+		"Document as YAML": {path: "/openapi/v2.yaml", status: http.StatusOK, marker: "version: v2"},
+		"Unknown version":  {path: "/openapi/v3.json", status: http.StatusNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			docs.HandleHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != tc.status {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.status)
+			}
+			if !strings.Contains(w.Body.String(), tc.marker) {
+				t.Errorf("body is expected to contain %q, have %q", tc.marker, w.Body.String())
+			}
+		})
+	}
+}
+
+func endpointsWith(t *testing.T, path, method string, e *endpoint.Endpoint) endpoint.Endpoints {
+	t.Helper()
+	endpoints := endpoint.Endpoints{}
+	if err := endpoints.Add(path, method, e); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return endpoints
+}
+
+func operationOf(t *testing.T, doc Doc, path, method string) *Operation {
+	t.Helper()
+	item, ok := doc.Paths[path]
+	if !ok {
+		t.Fatalf("path '%s' is not documented", path)
+	}
+	op := map[string]*Operation{
+		http.MethodGet:     item.Get,
+		http.MethodPut:     item.Put,
+		http.MethodPost:    item.Post,
+		http.MethodDelete:  item.Delete,
+		http.MethodOptions: item.Options,
+		http.MethodHead:    item.Head,
+		http.MethodPatch:   item.Patch,
+		http.MethodTrace:   item.Trace,
+	}[method]
+	if op == nil {
+		t.Fatalf("operation %s %s is not documented", method, path)
+	}
+	return op
+}
+
+func mediaTypes(c Content) []string {
+	out := []string{}
+	for mt := range c {
+		out = append(out, mt)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/invopop/jsonschema"
@@ -34,6 +35,24 @@ func (doc *Doc) HandleHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Docs holds a [Doc] per version of an API as generated by [FromEndpointsByVersion].
+type Docs map[string]Doc
+
+// HandleHTTP renders the Doc of the version named by the last segment of the
+// request path without its ending, such as '/openapi/v1.yaml', as YAML or JSON
+// based on the ending of the request path. Requests for unknown versions are
+// answered with '404 Not Found'.
+func (docs Docs) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	version := path.Base(r.URL.Path)
+	version = strings.TrimSuffix(version, path.Ext(version))
+	doc, ok := docs[version]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	doc.HandleHTTP(w, r)
+}
+
 // MarshalJSON is a bit of a hack to ensure that refereces are set properly in
 // the context of a OpenAPI Document. See [this pull request] for details.
 //
@@ -158,6 +177,29 @@ type Operation struct {
 	Deprecated   bool                  `json:"deprecated" yaml:"deprecated"`
 	Security     []SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers      []Server              `json:"servers,omitempty" yaml:"servers,omitempty"`
+	// Extensions are rendered as specification extensions, the keys are prefixed
+	// with 'x-' if required.
+	Extensions map[string]interface{} `json:"-" yaml:"-"`
+}
+
+// MarshalJSON renders the Extensions as members of the operation.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type alias Operation
+	raw, err := json.Marshal(alias(o))
+	if err != nil || len(o.Extensions) == 0 {
+		return raw, err
+	}
+	members := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, err
+	}
+	for k, v := range o.Extensions {
+		if !strings.HasPrefix(k, "x-") {
+			k = "x-" + k
+		}
+		members[k] = v
+	}
+	return json.Marshal(members)
 }
 
 // Parameter represents a [Parameter Object] according to the [OpenAPI Specification].
@@ -165,13 +207,16 @@ type Operation struct {
 // [Parameter Object]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#parameterObject
 // [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md
 type Parameter struct {
-	Name            string `json:"name" yaml:"name"`
-	In              string `json:"in" yaml:"in"`
-	Description     string `json:"description" yaml:"description"`
-	Required        bool   `json:"required" yaml:"required"`
-	Deprecated      bool   `json:"deprecated" yaml:"deprecated"`
-	AllowEmptyValue bool   `json:"allowEmptyValue" yaml:"allowEmptyValue"`
-	Schema          Schema `json:"schema" yaml:"schema"`
+	Name            string             `json:"name" yaml:"name"`
+	In              string             `json:"in" yaml:"in"`
+	Description     string             `json:"description" yaml:"description"`
+	Required        bool               `json:"required" yaml:"required"`
+	Deprecated      bool               `json:"deprecated" yaml:"deprecated"`
+	AllowEmptyValue bool               `json:"allowEmptyValue" yaml:"allowEmptyValue"`
+	Style           string             `json:"style,omitempty" yaml:"style,omitempty"`
+	Explode         *bool              `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Schema          Schema             `json:"schema" yaml:"schema"`
+	Examples        map[string]Example `json:"examples,omitempty" yaml:"examples,omitempty"`
 }
 
 // SecurityRequirement represents a [Security Requirement Object] according to the [OpenAPI Specification].
@@ -185,9 +230,16 @@ type SecurityRequirement map[string][]string
 // [Schema Object]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#schemaObject
 // [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md
 type Schema struct {
-	Type  string  `json:"type,omitempty" yaml:"type,omitempty"`
-	Ref   string  `json:"$ref,omitempty" yaml:"$ref,omitempty"`
-	Items *Schema `json:"items,omitempty" yaml:"items,omitempty"`
+	Type       string            `json:"type,omitempty" yaml:"type,omitempty"`
+	Format     string            `json:"format,omitempty" yaml:"format,omitempty"`
+	Ref        string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Items      *Schema           `json:"items,omitempty" yaml:"items,omitempty"`
+	Enum       []interface{}     `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum    *float64          `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum    *float64          `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	Pattern    string            `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Default    interface{}       `json:"default,omitempty" yaml:"default,omitempty"`
+	Properties map[string]Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
 }
 
 // Responses represents a [Responses Object] according to the [OpenAPI Specification].
@@ -201,8 +253,18 @@ type Responses map[string]Response
 // [Response Object]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#responseObject
 // [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md
 type Response struct {
-	Description string  `json:"description,omitempty" yaml:"description"`
-	Content     Content `json:"content" yaml:"content"`
+	Description string            `json:"description,omitempty" yaml:"description"`
+	Headers     map[string]Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     Content           `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// Header represents a [Header Object] according to the [OpenAPI Specification].
+//
+// [Header Object]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#headerObject
+// [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md
+type Header struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Schema      Schema `json:"schema" yaml:"schema"`
 }
 
 // Request represents a [Request Object] according to the [OpenAPI Specification].
@@ -223,7 +285,18 @@ type Request struct {
 type Content map[string]schemaDef
 
 type schemaDef struct {
-	Schema Schema `json:"schema" yaml:"schema"`
+	Schema   Schema             `json:"schema" yaml:"schema"`
+	Examples map[string]Example `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// Example represents an [Example Object] according to the [OpenAPI Specification].
+//
+// [Example Object]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#exampleObject
+// [OpenAPI Specification]: https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md
+type Example struct {
+	Summary     string      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Value       interface{} `json:"value" yaml:"value"`
 }
 
 // Components represents a [Components Object] according to the [OpenAPI Specification].
@@ -0,0 +1,130 @@
+package respond
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag returns a strong entity tag for the payload provided. Strong entity tags
+// change with every byte of the payload.
+func ETag(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// WeakETag returns a weak entity tag for the payload provided. Weak entity tags
+// mark representations which are semantically equivalent, such as the same data
+// rendered with a different indentation.
+func WeakETag(payload []byte) string {
+	return "W/" + ETag(payload)
+}
+
+// MatchETag reports whether the entity tag matches one of the entity tags of the
+// header value provided, such as the value of an 'If-Match' header. The value '*'
+// matches any entity tag. Weak entity tags never match if strong is true, which
+// is required for 'If-Match', the weak prefix is ignored otherwise. Entity tags
+// of the header value are quoted and can therefore contain commas, parsing stops
+// at the first malformed entity tag.
+func MatchETag(header, etag string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	if strong && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, candidate := range etags(header) {
+		if strong && strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// etags returns the entity tags listed in a header value.
+func etags(header string) []string {
+	out := []string{}
+	for {
+		header = strings.TrimLeft(header, " \t,")
+		prefix := ""
+		if strings.HasPrefix(header, "W/") {
+			prefix, header = "W/", header[2:]
+		}
+		if !strings.HasPrefix(header, `"`) {
+			return out
+		}
+		end := strings.Index(header[1:], `"`)
+		if end < 0 {
+			return out
+		}
+		out = append(out, prefix+header[:end+2])
+		header = header[end+2:]
+	}
+}
+
+// NotModified reports whether the representation identified by the entity tag
+// and date of last modification provided has not been modified since the client
+// received it according to the 'If-None-Match' and 'If-Modified-Since' headers of
+// the request. The 'If-Modified-Since' header is only evaluated if the request
+// does not provide an 'If-None-Match' header. Only GET and HEAD requests are
+// considered.
+func NotModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && MatchETag(inm, etag, false)
+	}
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(ims)
+}
+
+// Conditional responds like [Auto] but sets the 'ETag' header to the strong
+// entity tag of the rendered payload unless an 'ETag' header is provided. If
+// lastModified is not zero, the 'Last-Modified' header is set as well. Successful
+// responses to GET and HEAD requests are answered with '304 Not Modified' and
+// without body if the representation was not modified, see [NotModified].
+func Conditional(res http.ResponseWriter, req *http.Request, code int, data interface{}, lastModified time.Time, headers ...map[string]string) error {
+	buf := &bufferedWriter{header: http.Header{}}
+	if err := Auto(buf, req, code, data, headers...); err != nil {
+		return err
+	}
+	etag := buf.header.Get("ETag")
+	if etag == "" {
+		etag = ETag(buf.body.Bytes())
+	}
+	for k, v := range buf.header {
+		res.Header()[k] = v
+	}
+	res.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		res.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if code >= 200 && code < 300 && NotModified(req, etag, lastModified) {
+		res.Header().Del("Content-Type")
+		res.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	res.WriteHeader(code)
+	_, err := res.Write(buf.body.Bytes())
+	return err
+}
+
+// bufferedWriter holds a response in order to compute its entity tag.
+type bufferedWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header         { return b.header }
+func (b *bufferedWriter) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedWriter) WriteHeader(int)             {}
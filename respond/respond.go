@@ -5,26 +5,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/invopop/yaml"
 )
 
 const (
-	ContentTypeYAML = "text/yaml; charset=utf-8"        // default Content-Type when text/yaml is requested
-	ContentTypeJSON = "application/json; charset=utf-8" // default Content-Type when json is requested
-	ContentTypeRaw  = "text/plain; charset=utf-8"       // default Content-Type when rendering raw bytes
+	ContentTypeYAML        = "text/yaml; charset=utf-8"        // default Content-Type when text/yaml is requested
+	ContentTypeJSON        = "application/json; charset=utf-8" // default Content-Type when json is requested
+	ContentTypeRaw         = "text/plain; charset=utf-8"       // default Content-Type when rendering raw bytes
+	ContentTypeProblemJSON = "application/problem+json"        // Content-Type of problem details rendered as json
+	ContentTypeProblemYAML = "application/problem+yaml"        // Content-Type of problem details rendered as yaml
 )
 
 // Auto reads the 'accept' request header and tries to respond automatically with the appropriate
-// 'content-type'. This currently works for 'text/yaml', everything else will be threaded as
-// 'application/json'.
+// 'content-type'. This currently works for YAML (see [AcceptsYAML]) and for 'text/plain' if the data
+// implements [fmt.Stringer] (see [AcceptsText]), everything else will be threaded as 'application/json'.
 func Auto(res http.ResponseWriter, req *http.Request, code int, data interface{}, headers ...map[string]string) error {
-	switch req.Header.Get("Accept") {
-	case "text/yaml":
+	if AcceptsYAML(req) {
 		return YAML(res, code, data, headers...)
-	default:
-		return JSON(res, code, data, headers...)
 	}
+	if s, ok := data.(fmt.Stringer); ok && AcceptsText(req) {
+		Raw(res, code, []byte(s.String()), headers...)
+		return nil
+	}
+	return JSON(res, code, data, headers...)
+}
+
+// AcceptsText reports whether the 'accept' header of the request asks for plain text.
+func AcceptsText(req *http.Request) bool {
+	return acceptedMediaType(req) == "text/plain"
+}
+
+// AcceptsYAML reports whether the 'accept' header of the request asks for a YAML
+// document, which is the case for 'text/yaml', 'application/yaml' as well as any
+// media type with the '+yaml' suffix.
+func AcceptsYAML(req *http.Request) bool {
+	mediaType := acceptedMediaType(req)
+	switch {
+	case mediaType == "text/yaml", mediaType == "application/yaml":
+		return true
+	case strings.HasSuffix(mediaType, "+yaml"):
+		return true
+	}
+	return false
+}
+
+// acceptedMediaType returns the first media type of the 'accept' header of the request.
+func acceptedMediaType(req *http.Request) string {
+	accept := strings.SplitN(req.Header.Get("Accept"), ",", 2)[0]
+	return strings.TrimSpace(strings.SplitN(accept, ";", 2)[0])
 }
 
 // YAML uses 'github.com/invopop/yaml' to render the data provided as a YAML document. Head to the
@@ -33,7 +63,7 @@ func Auto(res http.ResponseWriter, req *http.Request, code int, data interface{}
 //
 // [official documentation]: https://github.com/invopop/yaml
 func YAML(res http.ResponseWriter, code int, data interface{}, headers ...map[string]string) error {
-	for k, v := range getHeaders(ContentTypeRaw, headers...) {
+	for k, v := range getHeaders(ContentTypeYAML, headers...) {
 		res.Header().Add(k, v)
 	}
 	out, err := yaml.Marshal(data)
@@ -50,7 +80,7 @@ func YAML(res http.ResponseWriter, code int, data interface{}, headers ...map[st
 //
 // [docs]: https://pkg.go.dev/encoding/json
 func JSON(res http.ResponseWriter, code int, data interface{}, headers ...map[string]string) error {
-	for k, v := range getHeaders(ContentTypeRaw, headers...) {
+	for k, v := range getHeaders(ContentTypeJSON, headers...) {
 		res.Header().Add(k, v)
 	}
 	out, err := json.MarshalIndent(data, "", "    ")
@@ -0,0 +1,282 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContentType(t *testing.T) {
+	cases := map[string]struct {
+		render      func(http.ResponseWriter, int, interface{}, ...map[string]string) error
+		headers     map[string]string
+		contentType string
+	}{
+		"JSON":                  {render: JSON, contentType: ContentTypeJSON},
+		"YAML":                  {render: YAML, contentType: ContentTypeYAML},
+		"Content-Type provided": {render: JSON, headers: map[string]string{"Content-Type": "application/problem+json"}, contentType: "application/problem+json"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := tc.render(w, http.StatusOK, map[string]string{"name": "shopping"}, tc.headers); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if have := w.Header().Values("Content-Type"); len(have) != 1 || have[0] != tc.contentType {
+				t.Errorf("Content-Type is not as expected, have %q, need %q", have, tc.contentType)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	cases := map[string]struct {
+		tag  func([]byte) string
+		weak bool
+	}{
+		"Strong entity tag": {tag: ETag},
+		"Weak entity tag":   {tag: WeakETag, weak: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tag := tc.tag([]byte(`{"name":"shopping"}`))
+			if strings.HasPrefix(tag, "W/") != tc.weak {
+				t.Errorf("weak prefix of %s is not as expected", tag)
+			}
+			opaque := strings.TrimPrefix(tag, "W/")
+			if len(opaque) < 3 || !strings.HasPrefix(opaque, `"`) || !strings.HasSuffix(opaque, `"`) {
+				t.Errorf("entity tag %s is expected to be quoted", tag)
+			}
+			if again := tc.tag([]byte(`{"name":"shopping"}`)); again != tag {
+				t.Errorf("entity tag is expected to be stable, have %s and %s", tag, again)
+			}
+			if other := tc.tag([]byte(`{"name": "shopping"}`)); other == tag {
+				t.Errorf("entity tag is expected to change with the payload, have %s for both", tag)
+			}
+		})
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	cases := map[string]struct {
+		header string
+		etag   string
+		strong bool
+		match  bool
+	}{
+		"Strong tags equal":                         {header: `"a"`, etag: `"a"`, strong: true, match: true},
+		"Strong tags differ":                        {header: `"a"`, etag: `"b"`, strong: true},
+		"Weak header tag in strong comparison":      {header: `W/"a"`, etag: `"a"`, strong: true},
+		"Weak entity tag in strong comparison":      {header: `"a"`, etag: `W/"a"`, strong: true},
+		"Weak header tag in weak comparison":        {header: `W/"a"`, etag: `"a"`, match: true},
+		"Weak entity tag in weak comparison":        {header: `"a"`, etag: `W/"a"`, match: true},
+		"Both weak in weak comparison":              {header: `W/"a"`, etag: `W/"a"`, match: true},
+		"Wildcard":                                  {header: "*", etag: `"a"`, strong: true, match: true},
+		"Wildcard with whitespace":                  {header: " * ", etag: `W/"a"`, match: true},
+		"List containing the tag":                   {header: `"x", "y",W/"a"`, etag: `"a"`, match: true},
+		"List not containing the tag":               {header: `"x", "y"`, etag: `"a"`},
+		"List with empty elements":                  {header: ` , "x",, "a" `, etag: `"a"`, strong: true, match: true},
+		"Quoted tag containing a comma":             {header: `"a,b"`, etag: `"a,b"`, strong: true, match: true},
+		"Quoted tag containing a comma in a list":   {header: `"x", "a,b"`, etag: `"a,b"`, strong: true, match: true},
+		"Part of a quoted tag containing a comma":   {header: `"a,b"`, etag: `"a"`},
+		"Unquoted tag":                              {header: `a`, etag: `"a"`},
+		"Unterminated quote":                        {header: `"a`, etag: `"a"`},
+		"Tag after a malformed tag":                 {header: `x, "a"`, etag: `"a"`},
+		"Empty header":                              {header: "", etag: `"a"`},
+		"Quotes are part of the comparison":         {header: `"a"`, etag: `a`},
+		"Whitespace inside quotes is significant":   {header: `" a"`, etag: `"a"`},
+		"Weak prefix must be uppercase to be weak":  {header: `w/"a"`, etag: `"a"`},
+		"Weak prefix without quotes is not matched": {header: `W/a`, etag: `"a"`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if match := MatchETag(tc.header, tc.etag, tc.strong); match != tc.match {
+				t.Errorf("match of %s against %s is not as expected, have %v, need %v", tc.etag, tc.header, match, tc.match)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		method       string
+		headers      map[string]string
+		etag         string
+		lastModified time.Time
+		notModified  bool
+	}{
+		"Without conditional headers": {
+			etag: `"a"`,
+		},
+		"Current entity tag": {
+			headers:     map[string]string{"If-None-Match": `"a"`},
+			etag:        `"a"`,
+			notModified: true,
+		},
+		"Current weak entity tag": {
+			headers:     map[string]string{"If-None-Match": `W/"a"`},
+			etag:        `"a"`,
+			notModified: true,
+		},
+		"Outdated entity tag": {
+			headers: map[string]string{"If-None-Match": `"b"`},
+			etag:    `"a"`,
+		},
+		"Wildcard": {
+			headers:     map[string]string{"If-None-Match": "*"},
+			etag:        `"a"`,
+			notModified: true,
+		},
+		"Without entity tag": {
+			headers: map[string]string{"If-None-Match": "*"},
+		},
+		"Head with current entity tag": {
+			method:      http.MethodHead,
+			headers:     map[string]string{"If-None-Match": `"a"`},
+			etag:        `"a"`,
+			notModified: true,
+		},
+		"Put with current entity tag": {
+			method:  http.MethodPut,
+			headers: map[string]string{"If-None-Match": `"a"`},
+			etag:    `"a"`,
+		},
+		"Not modified since": {
+			headers:      map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			lastModified: modified.Add(500 * time.Millisecond),
+			notModified:  true,
+		},
+		"Modified since": {
+			headers:      map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)},
+			lastModified: modified,
+		},
+		"Invalid date": {
+			headers:      map[string]string{"If-Modified-Since": "yesterday"},
+			lastModified: modified,
+		},
+		"Entity tag takes precedence over date": {
+			headers: map[string]string{
+				"If-None-Match":     `"b"`,
+				"If-Modified-Since": modified.Format(http.TimeFormat),
+			},
+			etag:         `"a"`,
+			lastModified: modified,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+			if notModified := NotModified(r, tc.etag, tc.lastModified); notModified != tc.notModified {
+				t.Errorf("result is not as expected, have %v, need %v", notModified, tc.notModified)
+			}
+		})
+	}
+}
+
+func TestConditional(t *testing.T) {
+	data := map[string]string{"name": "shopping"}
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	rendered := httptest.NewRecorder()
+	JSON(rendered, http.StatusOK, data)
+	current := ETag(rendered.Body.Bytes())
+
+	cases := map[string]struct {
+		method       string
+		status       int
+		headers      map[string]string
+		etag         string
+		lastModified time.Time
+		expected     int
+		body         bool
+	}{
+		"Without conditional headers": {
+			expected: http.StatusOK,
+			body:     true,
+		},
+		"Current entity tag": {
+			headers:  map[string]string{"If-None-Match": current},
+			expected: http.StatusNotModified,
+		},
+		"Outdated entity tag": {
+			headers:  map[string]string{"If-None-Match": `"outdated"`},
+			expected: http.StatusOK,
+			body:     true,
+		},
+		"Entity tag provided": {
+			headers:  map[string]string{"If-None-Match": `"custom"`},
+			etag:     `"custom"`,
+			expected: http.StatusNotModified,
+		},
+		"Not modified since": {
+			headers:      map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			lastModified: modified,
+			expected:     http.StatusNotModified,
+		},
+		"Unsuccessful response": {
+			status:   http.StatusNotFound,
+			headers:  map[string]string{"If-None-Match": "*"},
+			expected: http.StatusNotFound,
+			body:     true,
+		},
+		"Post with current entity tag": {
+			method:   http.MethodPost,
+			status:   http.StatusCreated,
+			headers:  map[string]string{"If-None-Match": current},
+			expected: http.StatusCreated,
+			body:     true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			method, status := tc.method, tc.status
+			if method == "" {
+				method = http.MethodGet
+			}
+			if status == 0 {
+				status = http.StatusOK
+			}
+			r := httptest.NewRequest(method, "/", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+			headers := map[string]string{}
+			if tc.etag != "" {
+				headers["ETag"] = tc.etag
+			}
+			w := httptest.NewRecorder()
+			if err := Conditional(w, r, status, data, tc.lastModified, headers); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if w.Code != tc.expected {
+				t.Errorf("status is not as expected, have %d, need %d", w.Code, tc.expected)
+			}
+			etag := tc.etag
+			if etag == "" {
+				etag = current
+			}
+			if have := w.Header().Get("ETag"); have != etag {
+				t.Errorf("ETag is not as expected, have %q, need %q", have, etag)
+			}
+			lastModified := ""
+			if !tc.lastModified.IsZero() {
+				lastModified = tc.lastModified.Format(http.TimeFormat)
+			}
+			if have := w.Header().Get("Last-Modified"); have != lastModified {
+				t.Errorf("Last-Modified is not as expected, have %q, need %q", have, lastModified)
+			}
+			if body := w.Body.Len() > 0; body != tc.body {
+				t.Errorf("presence of the body is not as expected, have %q", w.Body.String())
+			}
+		})
+	}
+}
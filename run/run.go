@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/apex/gateway"
 )
 
 // Run starts a web server in the mode provided. log is a function that takes only a string
-// so you can bring your own logging
+// so you can bring your own logging. When running as web server, the time allowed to read
+// the request headers and the time idle connections are kept open are bounded, see
+// [ReadHeaderTimeout] and [IdleTimeout].
 func Run(mode mode, listener string, handler http.Handler, log func(string)) error {
 	switch mode {
 	case ModeLocalServer:
@@ -17,7 +20,7 @@ func Run(mode mode, listener string, handler http.Handler, log func(string)) err
 			return fmt.Errorf("no listener defined")
 		}
 		log(fmt.Sprintf("Running locally at 'http://%s'...\n", listener))
-		return http.ListenAndServe(listener, handler)
+		return newServer(listener, handler).ListenAndServe()
 	case ModeAzureFunc:
 		port, ok := os.LookupEnv("FUNCTIONS_CUSTOMHANDLER_PORT")
 		if !ok {
@@ -25,7 +28,7 @@ func Run(mode mode, listener string, handler http.Handler, log func(string)) err
 		}
 		listener := fmt.Sprintf(":%s", port)
 		log(fmt.Sprintf("Running as Azure Function at '%s'...\n", listener))
-		return http.ListenAndServe(listener, handler)
+		return newServer(listener, handler).ListenAndServe()
 	case ModeAWSLambda:
 		log("Running as AWS Lambda...\n")
 		return gateway.ListenAndServe(listener, handler)
@@ -34,6 +37,23 @@ func Run(mode mode, listener string, handler http.Handler, log func(string)) err
 	}
 }
 
+const (
+	ReadHeaderTimeout = 10 * time.Second  // Time allowed to read the headers of a request.
+	IdleTimeout       = 120 * time.Second // Time an idle keep-alive connection is kept open.
+)
+
+// newServer returns a web server with bounded header read and idle times. Reading
+// the body and writing the response is bounded per endpoint, see the MaxBodyBytes
+// and Timeout of [github.com/unprofession-al/httpthings/endpoint.Endpoint].
+func newServer(listener string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              listener,
+		Handler:           handler,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+		IdleTimeout:       IdleTimeout,
+	}
+}
+
 // DetectRunMode tries to detect the run mode based on the environment variables present
 // at launch time. The order is:
 //  1. If `FUNCTIONS_CUSTOMHANDLER_PORT` is found, it is assumed that the function is